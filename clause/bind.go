@@ -0,0 +1,208 @@
+package clause
+
+import (
+	"database/sql/driver"
+	"reflect"
+	"strings"
+)
+
+// Rebind replaces every ? placeholder of query with bindVar(n), n counting from 1.
+// Placeholders inside quoted strings and identifiers are left untouched.
+func Rebind(query string, bindVar func(n int) string) string {
+	if strings.IndexByte(query, '?') < 0 {
+		return query
+	}
+
+	var (
+		builder = strings.Builder{}
+		quote   byte
+		n       int
+	)
+	builder.Grow(len(query) + 8)
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		switch {
+		case quote != 0:
+			// a doubled quote is an escaped one and keeps the literal open
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == '?':
+			n++
+			builder.WriteString(bindVar(n))
+			continue
+		}
+		builder.WriteByte(c)
+	}
+	return builder.String()
+}
+
+var valuerType = reflect.TypeOf((*driver.Valuer)(nil)).Elem()
+
+// Expand replaces the ? placeholder of every slice or array in vars with a group of
+// placeholders, one per element: ? becomes (?, ?, ?), or ?, ?, ? when the placeholder
+// is already in parentheses as in IN (?). Slices of slices become tuples ((?, ?), (?, ?)).
+// An empty slice turns col IN ? into 1 = 0 and col NOT IN ? into 1 = 1, and becomes
+// (NULL) elsewhere. Byte slices and driver.Valuer stay single values.
+func Expand(query string, vars []interface{}) (string, []interface{}) {
+	expand := false
+	for _, v := range vars {
+		if isList(v) {
+			expand = true
+			break
+		}
+	}
+	if !expand {
+		return query, vars
+	}
+
+	var (
+		builder  = strings.Builder{}
+		expanded = make([]interface{}, 0, len(vars))
+		quote    byte
+		n        int
+	)
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == '?' && n < len(vars):
+			v := vars[n]
+			n++
+			if !isList(v) {
+				expanded = append(expanded, v)
+				break
+			}
+
+			if reflect.ValueOf(v).Len() == 0 {
+				head, end := builder.String(), i
+				if inParens(query, i) {
+					head = strings.TrimRight(head, " \t\n")
+					head = head[:len(head)-1]
+					end = i + 1 + strings.IndexByte(query[i+1:], ')')
+				}
+				if condition, ok := emptyIn(head); ok {
+					builder.Reset()
+					builder.WriteString(condition)
+					i = end
+					continue
+				}
+			}
+
+			group, values := bindList(reflect.ValueOf(v))
+			if inParens(query, i) {
+				group = group[1 : len(group)-1]
+			}
+			builder.WriteString(group)
+			expanded = append(expanded, values...)
+			continue
+		}
+		builder.WriteByte(c)
+	}
+	return builder.String(), append(expanded, vars[n:]...)
+}
+
+// bindList returns the placeholder group of the slice or array v with its elements.
+func bindList(v reflect.Value) (string, []interface{}) {
+	if v.Len() == 0 {
+		return "(NULL)", nil
+	}
+
+	var values []interface{}
+	groups := make([]string, v.Len())
+	for i := 0; i < v.Len(); i++ {
+		elem := v.Index(i)
+		if isList(elem.Interface()) {
+			var tuple []interface{}
+			groups[i], tuple = bindList(reflect.ValueOf(elem.Interface()))
+			values = append(values, tuple...)
+			continue
+		}
+		groups[i] = "?"
+		values = append(values, elem.Interface())
+	}
+	return "(" + strings.Join(groups, ", ") + ")", values
+}
+
+// emptyIn replaces the "col IN" or "col NOT IN" that sql ends with, before the
+// placeholder of an empty slice, with the condition it amounts to.
+func emptyIn(sql string) (string, bool) {
+	s := strings.TrimRight(sql, " \t\n")
+	if len(s) < 3 || !strings.EqualFold(s[len(s)-2:], "IN") || !isSpace(s[len(s)-3]) {
+		return sql, false
+	}
+	s = strings.TrimRight(s[:len(s)-2], " \t\n")
+	condition := "1 = 0"
+	if len(s) > 3 && strings.EqualFold(s[len(s)-3:], "NOT") && isSpace(s[len(s)-4]) {
+		s = strings.TrimRight(s[:len(s)-3], " \t\n")
+		condition = "1 = 1"
+	}
+
+	start := operandStart(s)
+	if start == len(s) {
+		return sql, false
+	}
+	return s[:start] + condition, true
+}
+
+// operandStart returns where the column, qualified column or parenthesized tuple that s
+// ends with starts, len(s) when there is none.
+func operandStart(s string) int {
+	i := len(s)
+	if i > 0 && s[i-1] == ')' {
+		depth := 0
+		for i--; i >= 0; i-- {
+			switch s[i] {
+			case ')':
+				depth++
+			case '(':
+				if depth--; depth == 0 {
+					return i
+				}
+			}
+		}
+		return len(s)
+	}
+	for i > 0 && isOperandByte(s[i-1]) {
+		i--
+	}
+	return i
+}
+
+func isOperandByte(c byte) bool {
+	return c == '_' || c == '.' || c == '`' || c == '"' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9'
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n'
+}
+
+// isList reports whether v is a slice or array to expand into several placeholders.
+func isList(v interface{}) bool {
+	if v == nil {
+		return false
+	}
+	typ := reflect.TypeOf(v)
+	if typ.Implements(valuerType) {
+		return false
+	}
+	switch typ.Kind() {
+	case reflect.Slice, reflect.Array:
+		return typ.Elem().Kind() != reflect.Uint8
+	}
+	return false
+}
+
+// inParens reports whether the placeholder at i is alone inside parentheses.
+func inParens(query string, i int) bool {
+	before := strings.TrimRight(query[:i], " \t\n")
+	after := strings.TrimLeft(query[i+1:], " \t\n")
+	return strings.HasSuffix(before, "(") && strings.HasPrefix(after, ")")
+}
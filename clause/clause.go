@@ -14,6 +14,12 @@ const (
 	Update
 	Delete
 	Count
+	Replace
+	Returning
+	Conflict
+	Join
+	GroupBy
+	Having
 )
 
 type Clause struct {
@@ -21,6 +27,8 @@ type Clause struct {
 	sqlVars map[Type][]interface{}
 }
 
+// Set generates the clause name from vars, the table and column names in vars are
+// quoted by the caller for its dialect.
 func (c *Clause) Set(name Type, vars ...interface{}) {
 	if c.sql == nil {
 		c.sql = make(map[Type]string)
@@ -29,3 +29,75 @@ func TestCount(t *testing.T) {
 	fmt.Println(sql, vars)
 
 }
+
+func TestRebind(t *testing.T) {
+	dollar := func(n int) string { return fmt.Sprintf("$%d", n) }
+
+	tests := map[string]string{
+		"SELECT * FROM User":                                 "SELECT * FROM User",
+		"SELECT * FROM User WHERE Name = ? LIMIT ?":          "SELECT * FROM User WHERE Name = $1 LIMIT $2",
+		"SELECT * FROM User WHERE Name = '?' AND Age = ?":    "SELECT * FROM User WHERE Name = '?' AND Age = $1",
+		"SELECT * FROM User WHERE Name = 'it''s?' AND Age=?": "SELECT * FROM User WHERE Name = 'it''s?' AND Age=$1",
+		`SELECT "a?" FROM User WHERE Age IN (?, ?)`:          `SELECT "a?" FROM User WHERE Age IN ($1, $2)`,
+	}
+	for query, want := range tests {
+		if got := Rebind(query, dollar); got != want {
+			t.Fatalf("Rebind(%q) = %q, want %q", query, got, want)
+		}
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	var clause Clause
+	clause.Set(Update, "User", map[string]interface{}{"Name": "Tom", "Age": 18, "Email": "tom@example.com"})
+	sql, vars := clause.Build(Update)
+	if sql != "UPDATE User SET Age = ?, Email = ?, Name = ?" {
+		t.Fatal("failed to build SQL")
+	}
+	if !reflect.DeepEqual(vars, []interface{}{18, "tom@example.com", "Tom"}) {
+		t.Fatal("failed to build SQLVars")
+	}
+}
+
+func TestExpand(t *testing.T) {
+	tests := []struct {
+		query string
+		vars  []interface{}
+		sql   string
+		want  []interface{}
+	}{
+		{"id IN ?", []interface{}{[]int{1, 2, 3}}, "id IN (?, ?, ?)", []interface{}{1, 2, 3}},
+		{"id IN (?) AND name = ?", []interface{}{[2]string{"a", "b"}, "Tom"}, "id IN (?, ?) AND name = ?", []interface{}{"a", "b", "Tom"}},
+		{"id IN ? AND name = '?'", []interface{}{[]int{}}, "1 = 0 AND name = '?'", []interface{}{}},
+		{"age > ? AND user.id NOT IN (?) AND name = ?", []interface{}{18, []int{}, "Tom"}, "age > ? AND 1 = 1 AND name = ?", []interface{}{18, "Tom"}},
+		{"(a, b) in ?", []interface{}{[][]int{}}, "1 = 0", []interface{}{}},
+		{"VALUES ?", []interface{}{[]int{}}, "VALUES (NULL)", []interface{}{}},
+		{"data = ?", []interface{}{[]byte("raw")}, "data = ?", []interface{}{[]byte("raw")}},
+		{
+			"(a, b) IN ?",
+			[]interface{}{[][]interface{}{{1, "x"}, {2, "y"}}},
+			"(a, b) IN ((?, ?), (?, ?))",
+			[]interface{}{1, "x", 2, "y"},
+		},
+	}
+	for _, tt := range tests {
+		sql, vars := Expand(tt.query, tt.vars)
+		if sql != tt.sql || !reflect.DeepEqual(vars, tt.want) {
+			t.Fatalf("Expand(%q) = %q %v, want %q %v", tt.query, sql, vars, tt.sql, tt.want)
+		}
+	}
+}
+
+func TestGroupBy(t *testing.T) {
+	var clause Clause
+	clause.Set(Select, "User", []string{"Age", "count(*)"})
+	clause.Set(GroupBy, "Age", "Active")
+	clause.Set(Having, Gt("count(*)", 1))
+	sql, vars := clause.Build(Select, Where, GroupBy, Having)
+	if sql != "SELECT Age,count(*) FROM User GROUP BY Age, Active HAVING count(*) > ?" {
+		t.Fatal("failed to build SQL")
+	}
+	if !reflect.DeepEqual(vars, []interface{}{1}) {
+		t.Fatal("failed to build SQLVars")
+	}
+}
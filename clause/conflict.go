@@ -0,0 +1,10 @@
+package clause
+
+// OnConflict describes how an upsert handles the rows conflicting with existing ones,
+// the dialect renders it into ON CONFLICT or ON DUPLICATE KEY UPDATE.
+type OnConflict struct {
+	Columns   []string // 冲突列(唯一约束), 为空时使用主键
+	DoUpdates []string // 冲突时更新为插入值的列
+	DoNothing bool     // 冲突时保留已有行
+	UpdateAll bool     // 冲突时更新冲突列以外的所有列
+}
@@ -0,0 +1,195 @@
+package clause
+
+import "strings"
+
+// Expression is a condition of a WHERE clause, built into SQL with ? placeholders.
+type Expression interface {
+	Build() (sql string, vars []interface{})
+}
+
+type (
+	expr struct {
+		sql  string
+		vars []interface{}
+	}
+	compare struct {
+		column string
+		op     string
+		value  interface{}
+	}
+	in struct {
+		column string
+		values []interface{}
+	}
+	between struct {
+		column       string
+		lower, upper interface{}
+	}
+	isNull struct {
+		column string
+	}
+	and []Expression
+	or  []Expression
+	not []Expression
+)
+
+// Expr returns the raw condition sql with its vars, which may be named as by Named.
+func Expr(sql string, vars ...interface{}) Expression {
+	return expr{sql: sql, vars: vars}
+}
+
+// Eq returns column = value, or column IS NULL when value is nil.
+func Eq(column string, value interface{}) Expression {
+	return compare{column: column, op: "=", value: value}
+}
+
+// Neq returns column <> value, or column IS NOT NULL when value is nil.
+func Neq(column string, value interface{}) Expression {
+	return compare{column: column, op: "<>", value: value}
+}
+
+func Gt(column string, value interface{}) Expression {
+	return compare{column: column, op: ">", value: value}
+}
+
+func Gte(column string, value interface{}) Expression {
+	return compare{column: column, op: ">=", value: value}
+}
+
+func Lt(column string, value interface{}) Expression {
+	return compare{column: column, op: "<", value: value}
+}
+
+func Lte(column string, value interface{}) Expression {
+	return compare{column: column, op: "<=", value: value}
+}
+
+// Like returns column LIKE pattern.
+func Like(column string, pattern string) Expression {
+	return compare{column: column, op: "LIKE", value: pattern}
+}
+
+// In returns column IN (values), a condition matching no row when values is empty. A
+// single slice is expanded into its elements.
+func In(column string, values ...interface{}) Expression {
+	return in{column: column, values: values}
+}
+
+// Between returns column BETWEEN lower AND upper.
+func Between(column string, lower, upper interface{}) Expression {
+	return between{column: column, lower: lower, upper: upper}
+}
+
+func IsNull(column string) Expression {
+	return isNull{column: column}
+}
+
+// And joins exprs with AND, nested And are flattened.
+func And(exprs ...Expression) Expression {
+	var conditions and
+	for _, e := range exprs {
+		switch e := e.(type) {
+		case nil:
+		case and:
+			conditions = append(conditions, e...)
+		default:
+			conditions = append(conditions, e)
+		}
+	}
+	if len(conditions) == 1 {
+		return conditions[0]
+	}
+	return conditions
+}
+
+// Or joins exprs with OR, nested Or are flattened.
+func Or(exprs ...Expression) Expression {
+	var conditions or
+	for _, e := range exprs {
+		switch e := e.(type) {
+		case nil:
+		case or:
+			conditions = append(conditions, e...)
+		default:
+			conditions = append(conditions, e)
+		}
+	}
+	if len(conditions) == 1 {
+		return conditions[0]
+	}
+	return conditions
+}
+
+// Not negates exprs joined with AND.
+func Not(exprs ...Expression) Expression {
+	return not(exprs)
+}
+
+func (e expr) Build() (string, []interface{}) {
+	return Expand(Named(e.sql, e.vars))
+}
+
+func (c compare) Build() (string, []interface{}) {
+	if c.value == nil {
+		switch c.op {
+		case "=":
+			return c.column + " IS NULL", nil
+		case "<>":
+			return c.column + " IS NOT NULL", nil
+		}
+	}
+	return c.column + " " + c.op + " ?", []interface{}{c.value}
+}
+
+func (i in) Build() (string, []interface{}) {
+	if len(i.values) == 0 {
+		return "1 = 0", nil
+	}
+	return Expand(i.column+" IN ("+genBindVars(len(i.values))+")", i.values)
+}
+
+func (b between) Build() (string, []interface{}) {
+	return b.column + " BETWEEN ? AND ?", []interface{}{b.lower, b.upper}
+}
+
+func (n isNull) Build() (string, []interface{}) {
+	return n.column + " IS NULL", nil
+}
+
+func (a and) Build() (string, []interface{}) {
+	return join(a, " AND ")
+}
+
+func (o or) Build() (string, []interface{}) {
+	return join(o, " OR ")
+}
+
+func (n not) Build() (string, []interface{}) {
+	sql, vars := join(n, " AND ")
+	return "NOT (" + sql + ")", vars
+}
+
+// join builds exprs separated by sep, grouping the conditions that could bind looser
+// than sep in parentheses.
+func join(exprs []Expression, sep string) (string, []interface{}) {
+	var sqls []string
+	var vars []interface{}
+	for _, e := range exprs {
+		sql, v := e.Build()
+		if len(exprs) > 1 && grouped(e) {
+			sql = "(" + sql + ")"
+		}
+		sqls = append(sqls, sql)
+		vars = append(vars, v...)
+	}
+	return strings.Join(sqls, sep), vars
+}
+
+// grouped reports whether e needs parentheses when combined with other conditions.
+func grouped(e Expression) bool {
+	switch e.(type) {
+	case expr, and, or:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,62 @@
+package clause
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpression(t *testing.T) {
+	tests := []struct {
+		name string
+		expr Expression
+		sql  string
+		vars []interface{}
+	}{
+		{"eq", Eq("name", "Tom"), "name = ?", []interface{}{"Tom"}},
+		{"eq nil", Eq("name", nil), "name IS NULL", nil},
+		{"neq nil", Neq("name", nil), "name IS NOT NULL", nil},
+		{"gt", Gt("age", 18), "age > ?", []interface{}{18}},
+		{"in", In("id", 1, 2, 3), "id IN (?, ?, ?)", []interface{}{1, 2, 3}},
+		{"in empty", In("id"), "1 = 0", nil},
+		{"between", Between("age", 18, 30), "age BETWEEN ? AND ?", []interface{}{18, 30}},
+		{"like", Like("name", "T%"), "name LIKE ?", []interface{}{"T%"}},
+		{"is null", IsNull("deleted_at"), "deleted_at IS NULL", nil},
+		{
+			"and",
+			And(Eq("name", "Tom"), And(Gt("age", 18), Expr("a = ? OR b = ?", 1, 2))),
+			"name = ? AND age > ? AND (a = ? OR b = ?)",
+			[]interface{}{"Tom", 18, 1, 2},
+		},
+		{
+			"or",
+			Or(And(Eq("name", "Tom"), Gt("age", 18)), Eq("name", "Sam")),
+			"(name = ? AND age > ?) OR name = ?",
+			[]interface{}{"Tom", 18, "Sam"},
+		},
+		{"not", Not(Eq("name", "Tom")), "NOT (name = ?)", []interface{}{"Tom"}},
+		{
+			"not and",
+			Not(Or(Eq("a", 1), Eq("b", 2)), Eq("c", 3)),
+			"NOT ((a = ? OR b = ?) AND c = ?)",
+			[]interface{}{1, 2, 3},
+		},
+		{"single", And(nil, Eq("name", "Tom")), "name = ?", []interface{}{"Tom"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, vars := tt.expr.Build()
+			assert.Equal(t, tt.sql, sql)
+			assert.Equal(t, tt.vars, vars)
+		})
+	}
+}
+
+func TestWhereExpression(t *testing.T) {
+	var clause Clause
+	clause.Set(Select, "User", []string{"*"})
+	clause.Set(Where, And(Eq("Name", "Tom"), In("Age", 18, 20)))
+	sql, vars := clause.Build(Select, Where)
+	assert.Equal(t, "SELECT * FROM User WHERE Name = ? AND Age IN (?, ?)", sql)
+	assert.Equal(t, []interface{}{"Tom", 18, 20}, vars)
+}
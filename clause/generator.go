@@ -2,6 +2,7 @@ package clause
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 )
 
@@ -19,6 +20,12 @@ func init() {
 	generators[Update] = generatorUpdate
 	generators[Delete] = generatorDelete
 	generators[Count] = generatorCount
+	generators[Replace] = generatorReplace
+	generators[Returning] = generatorReturning
+	generators[Conflict] = generatorConflict
+	generators[Join] = generatorJoin
+	generators[GroupBy] = generatorGroupBy
+	generators[Having] = generatorHaving
 }
 
 func generatorCount(values ...interface{}) (string, []interface{}) {
@@ -33,11 +40,18 @@ func generatorUpdate(values ...interface{}) (string, []interface{}) {
 	// UPDATE $tableName SET ($fields)
 	tableName := values[0]
 	param := values[1].(map[string]interface{})
+	columns := make([]string, 0, len(param))
+	for k := range param {
+		columns = append(columns, k)
+	}
+	// 排序保证生成的SQL稳定
+	sort.Strings(columns)
+
 	var keys []string
 	var vars []interface{}
-	for k, v := range param {
+	for _, k := range columns {
 		keys = append(keys, k+" = ?")
-		vars = append(vars, v)
+		vars = append(vars, param[k])
 	}
 	return fmt.Sprintf("UPDATE %s SET %s", tableName, strings.Join(keys, ", ")), vars
 
@@ -50,6 +64,23 @@ func generatorInsert(values ...interface{}) (string, []interface{}) {
 	return fmt.Sprintf("INSERT INTO %s (%s)", tableName, fields), []interface{}{}
 }
 
+func generatorReplace(values ...interface{}) (string, []interface{}) {
+	// $replaceInto $tableName ($fields)
+	replaceInto, tableName := values[0], values[1]
+	fields := strings.Join(values[2].([]string), ",")
+	return fmt.Sprintf("%s %s (%s)", replaceInto, tableName, fields), []interface{}{}
+}
+
+func generatorReturning(values ...interface{}) (string, []interface{}) {
+	// RETURNING $columns, rendered by the dialect
+	return values[0].(string), []interface{}{}
+}
+
+func generatorConflict(values ...interface{}) (string, []interface{}) {
+	// ON CONFLICT ... / ON DUPLICATE KEY UPDATE ..., rendered by the dialect
+	return values[0].(string), []interface{}{}
+}
+
 func generatorValues(values ...interface{}) (string, []interface{}) {
 	// VALUES ($v1), ($v2), ...
 	var bindStr string
@@ -83,11 +114,42 @@ func generatorLimit(values ...interface{}) (string, []interface{}) {
 }
 
 func generatorWhere(values ...interface{}) (string, []interface{}) {
-	// WHERE $desc
-	desc, vars := values[0], values[1:]
+	// WHERE $desc, or WHERE $expression
+	if expression, ok := values[0].(Expression); ok {
+		sql, vars := expression.Build()
+		return fmt.Sprintf("WHERE %s", sql), vars
+	}
+	desc, vars := Expand(Named(fmt.Sprint(values[0]), values[1:]))
 	return fmt.Sprintf("WHERE %s", desc), vars
 }
 
+func generatorJoin(values ...interface{}) (string, []interface{}) {
+	// $join $join ..., every join is an Expression
+	var joins []string
+	var vars []interface{}
+	for _, value := range values {
+		sql, v := value.(Expression).Build()
+		joins = append(joins, sql)
+		vars = append(vars, v...)
+	}
+	return strings.Join(joins, " "), vars
+}
+
+func generatorGroupBy(values ...interface{}) (string, []interface{}) {
+	// GROUP BY $column, $column ...
+	columns := make([]string, len(values))
+	for i, value := range values {
+		columns[i] = fmt.Sprint(value)
+	}
+	return fmt.Sprintf("GROUP BY %s", strings.Join(columns, ", ")), []interface{}{}
+}
+
+func generatorHaving(values ...interface{}) (string, []interface{}) {
+	// HAVING $expression
+	sql, vars := values[0].(Expression).Build()
+	return fmt.Sprintf("HAVING %s", sql), vars
+}
+
 func generatorOrderBy(values ...interface{}) (string, []interface{}) {
 	return fmt.Sprintf("ORDER BY %s", values[0]), []interface{}{}
 }
@@ -0,0 +1,20 @@
+package clause
+
+// JoinTable joins Table to a statement on the condition On.
+type JoinTable struct {
+	Kind  string // INNER, LEFT, RIGHT..., 为空时即JOIN
+	Table string
+	On    Expression
+}
+
+func (j JoinTable) Build() (string, []interface{}) {
+	sql := "JOIN " + j.Table
+	if j.Kind != "" {
+		sql = j.Kind + " " + sql
+	}
+	if j.On == nil {
+		return sql, nil
+	}
+	on, vars := j.On.Build()
+	return sql + " ON " + on, vars
+}
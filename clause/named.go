@@ -0,0 +1,106 @@
+package clause
+
+import (
+	"database/sql"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/go-venus/venus/schema"
+)
+
+// Named replaces the @name and :name parameters of query with ? placeholders, taking
+// their values from the sql.NamedArg, map[string]interface{} and struct values of vars
+// by name. The fields of structs are named by their column as in schema.Parse, such as
+// created_at, or by their go name. The other vars stay bound to the ? placeholders of query in order. Postgres
+// casts (::), MySQL system variables (@@) and names without a value are left untouched.
+func Named(query string, vars []interface{}) (string, []interface{}) {
+	named := make(map[string]interface{})
+	var positional []interface{}
+	for _, v := range vars {
+		if !namedValues(v, named) {
+			positional = append(positional, v)
+		}
+	}
+	if len(named) == 0 {
+		return query, vars
+	}
+
+	var (
+		builder = strings.Builder{}
+		bound   = make([]interface{}, 0, len(vars))
+		quote   byte
+	)
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == '?' && len(positional) > 0:
+			bound = append(bound, positional[0])
+			positional = positional[1:]
+		case (c == '@' || c == ':') && i+1 < len(query) && query[i+1] == c:
+			// :: and @@ are not parameters
+			builder.WriteString(query[i : i+2])
+			i++
+			continue
+		case c == '@' || c == ':':
+			end := i + 1
+			for end < len(query) && isNameByte(query[end], end == i+1) {
+				end++
+			}
+			if value, ok := named[query[i+1:end]]; ok && end > i+1 {
+				builder.WriteByte('?')
+				bound = append(bound, value)
+				i = end - 1
+				continue
+			}
+		}
+		builder.WriteByte(c)
+	}
+	return builder.String(), append(bound, positional...)
+}
+
+// namedValues adds the named values held by v to named, it reports false when v is a
+// positional value.
+func namedValues(v interface{}, named map[string]interface{}) bool {
+	switch v := v.(type) {
+	case sql.NamedArg:
+		named[v.Name] = v.Value
+		return true
+	case map[string]interface{}:
+		for name, value := range v {
+			named[name] = value
+		}
+		return true
+	case time.Time:
+		return false
+	}
+
+	rv := reflect.Indirect(reflect.ValueOf(v))
+	if !rv.IsValid() || rv.Kind() != reflect.Struct || rv.Type().Implements(valuerType) ||
+		reflect.PtrTo(rv.Type()).Implements(valuerType) {
+		return false
+	}
+	table, err := schema.DefaultRegistry.Parse(rv.Interface())
+	if err != nil {
+		return false
+	}
+	// a copy that cannot be set, nil embedded pointers of v are left alone
+	rv = reflect.ValueOf(rv.Interface())
+	for _, field := range table.Fields {
+		if value := field.ReflectValueOf(rv); value.IsValid() {
+			named[field.StructName] = value.Interface()
+			named[field.Name] = value.Interface()
+		}
+	}
+	return true
+}
+
+func isNameByte(c byte, first bool) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (!first && c >= '0' && c <= '9')
+}
@@ -0,0 +1,74 @@
+package clause
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNamed(t *testing.T) {
+	since := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name  string
+		query string
+		vars  []interface{}
+		sql   string
+		want  []interface{}
+	}{
+		{
+			name:  "sql.Named",
+			query: "name = @name OR nick = @name",
+			vars:  []interface{}{sql.Named("name", "Tom")},
+			sql:   "name = ? OR nick = ?",
+			want:  []interface{}{"Tom", "Tom"},
+		},
+		{
+			name:  "map",
+			query: "age > :age AND created_at > :since AND tag = ':age'",
+			vars:  []interface{}{map[string]interface{}{"age": 18, "since": since}},
+			sql:   "age > ? AND created_at > ? AND tag = ':age'",
+			want:  []interface{}{18, since},
+		},
+		{
+			name:  "struct",
+			query: "name = @Name AND age = ?",
+			vars:  []interface{}{struct{ Name string }{"Tom"}, 18},
+			sql:   "name = ? AND age = ?",
+			want:  []interface{}{"Tom", 18},
+		},
+		{
+			name:  "struct columns",
+			query: "name = @name AND created_at > :created_at AND code = @sku",
+			vars: []interface{}{&struct {
+				Name      string
+				CreatedAt time.Time
+				Code      string `venus:"column:sku"`
+			}{"Tom", since, "A-1"}},
+			sql:  "name = ? AND created_at > ? AND code = ?",
+			want: []interface{}{"Tom", since, "A-1"},
+		},
+		{
+			name:  "casts and system variables",
+			query: "id = :id::bigint AND @@session.time_zone = @tz AND @unknown",
+			vars:  []interface{}{sql.Named("id", 1), sql.Named("tz", "UTC")},
+			sql:   "id = ?::bigint AND @@session.time_zone = ? AND @unknown",
+			want:  []interface{}{1, "UTC"},
+		},
+		{
+			name:  "positional",
+			query: "created_at > ?",
+			vars:  []interface{}{since},
+			sql:   "created_at > ?",
+			want:  []interface{}{since},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, vars := Named(tt.query, tt.vars)
+			assert.Equal(t, tt.sql, sql)
+			assert.Equal(t, tt.want, vars)
+		})
+	}
+}
@@ -0,0 +1,20 @@
+package main
+
+import (
+	"errors"
+
+	"github.com/go-venus/venus"
+)
+
+// loadConfig loads the config file at path, if any, with its environment overrides
+// and checks that it names a database driver.
+func loadConfig(path string) (*venus.Config, error) {
+	config, err := venus.LoadConfig(path)
+	if err != nil {
+		return nil, err
+	}
+	if config.Driver == "" {
+		return nil, errors.New("no database driver configured, set it in the config file or $VENUS_DRIVER")
+	}
+	return config, nil
+}
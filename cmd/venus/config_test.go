@@ -0,0 +1,29 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "venus.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("driver: mysql\nsource: root@/app\n"), 0o644))
+
+	config, err := loadConfig(path)
+	require.NoError(t, err)
+	assert.Equal(t, "mysql", config.Driver)
+	assert.Equal(t, "root@/app", config.Source)
+
+	t.Setenv("VENUS_SOURCE", "root@/other")
+	config, err = loadConfig(path)
+	require.NoError(t, err)
+	assert.Equal(t, "root@/other", config.Source)
+
+	t.Setenv("VENUS_DRIVER", "")
+	_, err = loadConfig(path)
+	assert.Error(t, err)
+}
@@ -0,0 +1,102 @@
+// Command venus runs migrations and schema operations against the database
+// described by a venus.Config.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+
+	_ "github.com/go-sql-driver/mysql"
+	"github.com/go-venus/venus"
+	_ "github.com/lib/pq"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+const usage = `Usage: venus [flags] <command> [arguments]
+
+Commands:
+  migrate up              apply all pending migrations
+  migrate down [n]        revert the last n migrations, 1 by default
+  migrate status          show the state of every migration
+  migrate unlock          release the lock left by a migration run that crashed
+  migrate create <name>   create an empty up and down migration
+  schema dump             print the tables and columns of the database
+  db ping                 check that the database is reachable
+
+Flags:
+`
+
+var errUsage = errors.New("invalid usage")
+
+func main() {
+	flags := flag.NewFlagSet("venus", flag.ExitOnError)
+	configPath := flags.String("config", os.Getenv("VENUS_CONFIG"), "path of the YAML config file, $VENUS_CONFIG")
+	dir := flags.String("dir", envOr("VENUS_MIGRATIONS", "migrations"), "directory of the migration files, $VENUS_MIGRATIONS")
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), usage)
+		flags.PrintDefaults()
+	}
+	_ = flags.Parse(os.Args[1:])
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	err := run(ctx, *configPath, *dir, flags.Args())
+	if errors.Is(err, errUsage) {
+		flags.Usage()
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "venus:", err)
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, configPath, dir string, args []string) error {
+	if len(args) < 2 {
+		return errUsage
+	}
+
+	// creating a migration does not need a database
+	if args[0] == "migrate" && args[1] == "create" {
+		if len(args) != 3 {
+			return errUsage
+		}
+		return createMigration(dir, args[2])
+	}
+
+	config, err := loadConfig(configPath)
+	if err != nil {
+		return err
+	}
+	engine, err := venus.OpenContext(ctx, config)
+	if err != nil {
+		return err
+	}
+	defer engine.Close()
+
+	switch {
+	case args[0] == "migrate":
+		return runMigrate(ctx, engine, dir, args[1:])
+	case args[0] == "schema" && args[1] == "dump":
+		return dumpSchema(ctx, engine, os.Stdout)
+	case args[0] == "db" && args[1] == "ping":
+		if err = engine.Ping(ctx); err != nil {
+			return err
+		}
+		fmt.Println("ok")
+		return nil
+	}
+	return errUsage
+}
+
+func envOr(key, fallback string) string {
+	if value, ok := os.LookupEnv(key); ok {
+		return value
+	}
+	return fallback
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRun(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	t.Setenv("VENUS_DRIVER", "sqlite3")
+	t.Setenv("VENUS_SOURCE", filepath.Join(t.TempDir(), "venus.db"))
+
+	for _, args := range [][]string{nil, {"migrate"}, {"migrate", "create"}, {"migrate", "sideways"}, {"db", "drop"}} {
+		assert.ErrorIs(t, run(ctx, "", dir, args), errUsage, args)
+	}
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "1_create_users.up.sql"), []byte("CREATE TABLE users (id integer PRIMARY KEY);"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "1_create_users.down.sql"), []byte("DROP TABLE users;"), 0o644))
+	for _, args := range [][]string{{"db", "ping"}, {"migrate", "up"}, {"migrate", "status"}, {"migrate", "down"}, {"migrate", "unlock"}} {
+		assert.NoError(t, run(ctx, "", dir, args), args)
+	}
+	assert.EqualError(t, run(ctx, "", dir, []string{"migrate", "down", "0"}), `invalid number of migrations "0"`)
+
+	t.Setenv("VENUS_DRIVER", "")
+	assert.Error(t, run(ctx, "", dir, []string{"db", "ping"}))
+}
@@ -0,0 +1,20 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateMigration(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, createMigration(dir, "create_users"))
+
+	files, err := filepath.Glob(filepath.Join(dir, "*_create_users.*.sql"))
+	require.NoError(t, err)
+	assert.Len(t, files, 2)
+
+	assert.Error(t, createMigration(dir, "drop users"))
+}
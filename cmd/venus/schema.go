@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/go-venus/venus"
+	"github.com/go-venus/venus/clause"
+)
+
+// dumpSchema prints the columns of every table of the database.
+func dumpSchema(ctx context.Context, engine *venus.Engine, out io.Writer) error {
+	query, args := engine.Dialect().TablesSQL()
+	tables, err := queryStrings(ctx, engine, query, args...)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "TABLE\tCOLUMN\tTYPE\tSIZE")
+	for _, table := range tables {
+		if err = dumpTable(ctx, engine, w, table); err != nil {
+			return err
+		}
+	}
+	return w.Flush()
+}
+
+func dumpTable(ctx context.Context, engine *venus.Engine, w io.Writer, table string) error {
+	query, args := engine.Dialect().ColumnsSQL(table)
+	rows, err := engine.DB().QueryContext(ctx, clause.Rebind(query, engine.Dialect().BindVar), args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			name, dataType string
+			size           sql.NullInt64
+		)
+		if err = rows.Scan(&name, &dataType, &size); err != nil {
+			return err
+		}
+		var sizeText string
+		if size.Valid {
+			sizeText = fmt.Sprint(size.Int64)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", table, name, dataType, sizeText)
+	}
+	return rows.Err()
+}
+
+func queryStrings(ctx context.Context, engine *venus.Engine, query string, args ...any) (values []string, err error) {
+	rows, err := engine.DB().QueryContext(ctx, clause.Rebind(query, engine.Dialect().BindVar), args...)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var value string
+		if err = rows.Scan(&value); err != nil {
+			return
+		}
+		values = append(values, value)
+	}
+	return values, rows.Err()
+}
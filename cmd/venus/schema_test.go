@@ -0,0 +1,31 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-venus/venus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDumpSchema(t *testing.T) {
+	ctx := context.Background()
+	engine, err := venus.OpenContext(ctx, &venus.Config{Driver: "sqlite3", Source: filepath.Join(t.TempDir(), "venus.db")})
+	require.NoError(t, err)
+	defer engine.Close()
+
+	_, err = engine.DB().Exec("CREATE TABLE users (id integer PRIMARY KEY, name varchar(64))")
+	require.NoError(t, err)
+	_, err = engine.DB().Exec("CREATE TABLE accounts (id integer PRIMARY KEY)")
+	require.NoError(t, err)
+
+	var out bytes.Buffer
+	require.NoError(t, dumpSchema(ctx, engine, &out))
+	assert.Equal(t, "TABLE     COLUMN  TYPE         SIZE\n"+
+		"accounts  id      INTEGER      \n"+
+		"users     id      INTEGER      \n"+
+		"users     name    varchar(64)  \n", out.String())
+}
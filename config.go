@@ -1,6 +1,120 @@
 package venus
 
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-venus/venus/schema"
+	"gopkg.in/yaml.v3"
+)
+
+// Config describes the database to open. Zero pool settings keep the database/sql defaults.
 type Config struct {
 	Driver string `yaml:"driver"`
 	Source string `yaml:"source"`
+
+	MaxOpenConns    int           `yaml:"max_open_conns"`
+	MaxIdleConns    int           `yaml:"max_idle_conns"`
+	ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime"`
+	ConnMaxIdleTime time.Duration `yaml:"conn_max_idle_time"`
+
+	PingTimeout          time.Duration `yaml:"ping_timeout"`            // 每次ping的超时时间, 0表示不限制
+	OpenRetries          int           `yaml:"open_retries"`            // ping失败后的重试次数
+	OpenRetryInterval    time.Duration `yaml:"open_retry_interval"`     // 首次重试间隔, 之后每次翻倍, 默认100ms
+	OpenRetryMaxInterval time.Duration `yaml:"open_retry_max_interval"` // 重试间隔上限, 默认30s
+
+	// NamingStrategy names tables and columns, snake_case by default.
+	NamingStrategy schema.Namer `yaml:"-"`
+}
+
+// LoadConfig reads the YAML config file at path, or starts from an empty config when
+// path is "", then overrides every setting found in the environment as VENUS_<YAML KEY>,
+// such as VENUS_SOURCE or VENUS_MAX_OPEN_CONNS.
+func LoadConfig(path string) (*Config, error) {
+	config := &Config{}
+	if path != "" {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if err = yaml.Unmarshal(content, config); err != nil {
+			return nil, fmt.Errorf("parse config %s: %w", path, err)
+		}
+	}
+
+	if err := loadEnv(config); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// loadEnv overrides the fields of the struct dest points to with the environment
+// variables named VENUS_<YAML KEY>. Fields without a yaml key are skipped.
+func loadEnv(dest interface{}) error {
+	value := reflect.ValueOf(dest).Elem()
+	for i := 0; i < value.NumField(); i++ {
+		name, _, _ := strings.Cut(value.Type().Field(i).Tag.Get("yaml"), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+		key := "VENUS_" + strings.ToUpper(name)
+		env, ok := os.LookupEnv(key)
+		if !ok {
+			continue
+		}
+		if err := setEnv(value.Field(i), env); err != nil {
+			return fmt.Errorf("invalid %s: %w", key, err)
+		}
+	}
+	return nil
+}
+
+// setEnv parses env into field according to its kind.
+func setEnv(field reflect.Value, env string) error {
+	if field.Type() == durationType {
+		d, err := time.ParseDuration(env)
+		if err != nil {
+			return err
+		}
+		field.SetInt(int64(d))
+		return nil
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(env)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(env)
+		if err != nil {
+			return err
+		}
+		field.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(env, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(env, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(env, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetFloat(f)
+	default:
+		return fmt.Errorf("unsupported type %s", field.Type())
+	}
+	return nil
 }
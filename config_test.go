@@ -0,0 +1,79 @@
+package venus
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "venus.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+driver: mysql
+source: root@/app
+max_open_conns: 20
+conn_max_lifetime: 5m
+`), 0o644))
+
+	config, err := LoadConfig(path)
+	require.NoError(t, err)
+	assert.Equal(t, &Config{
+		Driver:          "mysql",
+		Source:          "root@/app",
+		MaxOpenConns:    20,
+		ConnMaxLifetime: 5 * time.Minute,
+	}, config)
+
+	t.Setenv("VENUS_SOURCE", "root@/other")
+	t.Setenv("VENUS_MAX_IDLE_CONNS", "4")
+	t.Setenv("VENUS_PING_TIMEOUT", "2s")
+	config, err = LoadConfig(path)
+	require.NoError(t, err)
+	assert.Equal(t, "root@/other", config.Source)
+	assert.Equal(t, 20, config.MaxOpenConns)
+	assert.Equal(t, 4, config.MaxIdleConns)
+	assert.Equal(t, 2*time.Second, config.PingTimeout)
+
+	t.Setenv("VENUS_OPEN_RETRIES", "many")
+	_, err = LoadConfig(path)
+	assert.Error(t, err)
+
+	_, err = LoadConfig(filepath.Join(t.TempDir(), "missing.yaml"))
+	assert.Error(t, err)
+}
+
+func TestLoadEnv(t *testing.T) {
+	var settings struct {
+		Name     string        `yaml:"name,omitempty"`
+		Debug    bool          `yaml:"debug"`
+		Ratio    float64       `yaml:"ratio"`
+		Size     uint16        `yaml:"size"`
+		Timeout  time.Duration `yaml:"timeout"`
+		Untagged string
+		Tags     []string `yaml:"tags"`
+	}
+	t.Setenv("VENUS_NAME", "venus")
+	t.Setenv("VENUS_DEBUG", "true")
+	t.Setenv("VENUS_RATIO", "0.5")
+	t.Setenv("VENUS_SIZE", "64")
+	t.Setenv("VENUS_TIMEOUT", "1m")
+	t.Setenv("VENUS_", "ignored")
+	require.NoError(t, loadEnv(&settings))
+	assert.Equal(t, "venus", settings.Name)
+	assert.True(t, settings.Debug)
+	assert.Equal(t, 0.5, settings.Ratio)
+	assert.EqualValues(t, 64, settings.Size)
+	assert.Equal(t, time.Minute, settings.Timeout)
+	assert.Empty(t, settings.Untagged)
+
+	t.Setenv("VENUS_SIZE", "65536")
+	assert.Error(t, loadEnv(&settings))
+	t.Setenv("VENUS_SIZE", "64")
+
+	t.Setenv("VENUS_TAGS", "a,b")
+	assert.EqualError(t, loadEnv(&settings), "invalid VENUS_TAGS: unsupported type []string")
+}
@@ -2,7 +2,11 @@ package dialect
 
 import (
 	"errors"
+	"strings"
 	"sync"
+
+	"github.com/go-venus/venus/clause"
+	"github.com/go-venus/venus/schema"
 )
 
 var (
@@ -10,25 +14,105 @@ var (
 	dialectsMap = map[string]Dialect{}
 )
 
-var ErrNotFoundDialect = errors.New("not found dialect")
+var (
+	ErrNotFoundDialect = errors.New("not found dialect")
+	ErrNotSupported    = errors.New("not supported by dialect")
+)
 
 // Dialect getDB Dialect
 type Dialect interface {
+	// TableExistSQL returns the query used to check whether a table exists.
 	TableExistSQL(tableName string) (string, []any)
+	// Quote quotes an identifier, qualified names such as table.column are quoted per part.
+	Quote(name string) string
+	// DataTypeOf returns the column type of a field, or "" when it is not supported.
+	DataTypeOf(field *schema.Field) string
+	// AutoIncrement returns the keyword following the primary key of an auto increment column,
+	// "" when the dialect uses a dedicated column type instead.
+	AutoIncrement() string
+	// ColumnComment returns the comment as part of the column definition, or as a statement
+	// to run after the table is created. Both are empty when comments are not supported.
+	ColumnComment(tableName, columnName, comment string) (inline string, stmt string)
+	// TablesSQL returns the query listing the names of the tables of the database.
+	TablesSQL() (string, []any)
+	// ColumnsSQL returns the query listing the columns of a table as rows of
+	// (name, data type, character length or NULL).
+	ColumnsSQL(tableName string) (string, []any)
+	// IndexesSQL returns the query listing the indexes of a table as rows of
+	// (index name, unique, column name) ordered by index name and column position.
+	IndexesSQL(tableName string) (string, []any)
+	// ModifyColumnSQL returns the statement changing an existing column to the definition
+	// of field, "" when the dialect cannot alter columns.
+	ModifyColumnSQL(tableName string, field *schema.Field, definition string) string
+	// BindVar returns the placeholder of the n-th (1 based) bind variable.
+	BindVar(n int) string
+	// OnConflict returns the clause following the VALUES of an upsert, conflict has its
+	// Columns and DoUpdates resolved.
+	OnConflict(conflict clause.OnConflict) string
+}
+
+// Replacer is implemented by dialects that can replace conflicting rows on insert.
+type Replacer interface {
+	// ReplaceInto returns the keywords used in place of INSERT INTO.
+	ReplaceInto() string
+}
+
+// Returner is implemented by dialects returning the columns of inserted rows with
+// INSERT ... RETURNING.
+type Returner interface {
+	// Returning returns the RETURNING clause of columns.
+	Returning(columns ...string) string
+}
+
+// InsertIDer is implemented by dialects deriving the keys generated by an insert from
+// sql.Result.LastInsertId.
+type InsertIDer interface {
+	// InsertIDs returns the keys of the n rows inserted by a statement reporting lastInsertID,
+	// nil when they cannot be derived.
+	InsertIDs(lastInsertID int64, n int) []int64
+}
+
+// onConflict renders conflict as ON CONFLICT (...) DO UPDATE SET col = EXCLUDED.col
+// or DO NOTHING, the syntax shared by postgres and sqlite.
+func onConflict(d Dialect, conflict clause.OnConflict) string {
+	columns := make([]string, len(conflict.Columns))
+	for i, column := range conflict.Columns {
+		columns[i] = d.Quote(column)
+	}
+
+	target := "ON CONFLICT"
+	if len(columns) > 0 {
+		target += " (" + strings.Join(columns, ", ") + ")"
+	}
+	if conflict.DoNothing {
+		return target + " DO NOTHING"
+	}
+
+	assignments := make([]string, len(conflict.DoUpdates))
+	for i, column := range conflict.DoUpdates {
+		column = d.Quote(column)
+		assignments[i] = column + " = EXCLUDED." + column
+	}
+	return target + " DO UPDATE SET " + strings.Join(assignments, ", ")
+}
+
+// quoteString quotes s as a SQL string literal.
+func quoteString(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
 }
 
 // RegisterDialect Register Dialect.
 func RegisterDialect(name string, dialect Dialect) {
-	rw.RLock()
-	defer rw.RUnlock()
+	rw.Lock()
+	defer rw.Unlock()
 
 	dialectsMap[name] = dialect
 }
 
 // GetDialect Get Dialect.
 func GetDialect(name string) (dialect Dialect, err error) {
-	rw.Lock()
-	defer rw.Unlock()
+	rw.RLock()
+	defer rw.RUnlock()
 
 	var ok bool
 	if dialect, ok = dialectsMap[name]; !ok {
@@ -0,0 +1,164 @@
+package dialect
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/go-venus/venus/clause"
+	"github.com/go-venus/venus/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+type dataTypes struct {
+	Bool    bool
+	Int8    int8
+	Int     int
+	Int32P  *int32
+	Uint16  uint16
+	Float   float64
+	Decimal float64 `venus:"precision:10;scale:2"`
+	String  string
+	Varchar string `venus:"size:64"`
+	Bytes   []byte
+	Time    time.Time
+	Null    sql.NullInt32
+	Custom  string `venus:"type:json"`
+	Generic string `venus:"type:int"`
+	Ints    []int
+}
+
+func assertDataTypes(t *testing.T, d Dialect, want map[string]string) {
+	table := schema.Parse(&dataTypes{})
+	for name, dataType := range want {
+		assert.Equal(t, dataType, d.DataTypeOf(table.GetField(name)), name)
+	}
+}
+
+func TestGetDialect(t *testing.T) {
+	d, err := GetDialect("mysql")
+	assert.NoError(t, err)
+	assert.NotNil(t, d)
+
+	_, err = GetDialect("unknown")
+	assert.ErrorIs(t, err, ErrNotFoundDialect)
+}
+
+func TestMysql(t *testing.T) {
+	d, _ := GetDialect("mysql")
+
+	assert.Equal(t, "`user`", d.Quote("user"))
+	assert.Equal(t, "`user`.`name`", d.Quote("user.name"))
+	assert.Equal(t, "`user`.*", d.Quote("user.*"))
+	assert.Equal(t, "`a``b`", d.Quote("a`b"))
+
+	sql, vars := d.TableExistSQL("user")
+	assert.Contains(t, sql, "information_schema.tables")
+	assert.Equal(t, []any{"user"}, vars)
+
+	assertDataTypes(t, d, map[string]string{
+		"bool":    "boolean",
+		"int8":    "tinyint",
+		"int":     "bigint",
+		"int32_p": "int",
+		"uint16":  "smallint unsigned",
+		"float":   "double",
+		"decimal": "decimal(10, 2)",
+		"string":  "varchar(255)",
+		"varchar": "varchar(64)",
+		"bytes":   "longblob",
+		"time":    "datetime(3)",
+		"null":    "int",
+		"custom":  "json",
+		"generic": "int",
+		"ints":    "",
+	})
+}
+
+func TestPostgres(t *testing.T) {
+	d, err := GetDialect("postgres")
+	assert.NoError(t, err)
+
+	assert.Equal(t, `"user"`, d.Quote("user"))
+	assert.Equal(t, `"public"."user"`, d.Quote("public.user"))
+	assert.Equal(t, "$1", d.BindVar(1))
+	assert.Equal(t, "$12", d.BindVar(12))
+
+	sql, vars := d.TableExistSQL("user")
+	assert.Contains(t, sql, "CURRENT_SCHEMA()")
+	assert.Equal(t, []any{"user"}, vars)
+
+	assertDataTypes(t, d, map[string]string{
+		"bool":    "boolean",
+		"int8":    "smallint",
+		"int":     "bigint",
+		"uint16":  "integer",
+		"decimal": "numeric(10, 2)",
+		"string":  "text",
+		"varchar": "varchar(64)",
+		"bytes":   "bytea",
+		"time":    "timestamptz",
+		"custom":  "json",
+		"generic": "int",
+	})
+}
+
+func TestSQLite(t *testing.T) {
+	for _, name := range []string{"sqlite3", "sqlite"} {
+		d, err := GetDialect(name)
+		assert.NoError(t, err)
+
+		assert.Equal(t, `"user"`, d.Quote("user"))
+		assert.Equal(t, "?", d.BindVar(3))
+
+		sql, vars := d.TableExistSQL("user")
+		assert.Contains(t, sql, "sqlite_master")
+		assert.Equal(t, []any{"user"}, vars)
+
+		assertDataTypes(t, d, map[string]string{
+			"uint16":  "integer",
+			"float":   "real",
+			"varchar": "text",
+			"bytes":   "blob",
+			"time":    "datetime",
+		})
+
+		replacer, ok := d.(Replacer)
+		assert.True(t, ok)
+		assert.Equal(t, "INSERT OR REPLACE INTO", replacer.ReplaceInto())
+	}
+}
+
+func TestOnConflict(t *testing.T) {
+	update := clause.OnConflict{Columns: []string{"code"}, DoUpdates: []string{"name", "price"}}
+	nothing := clause.OnConflict{Columns: []string{"id"}, DoNothing: true}
+
+	tests := []struct {
+		dialect        string
+		update, ignore string
+	}{
+		{
+			dialect: "mysql",
+			update:  "ON DUPLICATE KEY UPDATE `name` = VALUES(`name`), `price` = VALUES(`price`)",
+			ignore:  "ON DUPLICATE KEY UPDATE `id` = `id`",
+		},
+		{
+			dialect: "postgres",
+			update:  `ON CONFLICT ("code") DO UPDATE SET "name" = EXCLUDED."name", "price" = EXCLUDED."price"`,
+			ignore:  `ON CONFLICT ("id") DO NOTHING`,
+		},
+		{
+			dialect: "sqlite3",
+			update:  `ON CONFLICT ("code") DO UPDATE SET "name" = EXCLUDED."name", "price" = EXCLUDED."price"`,
+			ignore:  `ON CONFLICT ("id") DO NOTHING`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.dialect, func(t *testing.T) {
+			d, err := GetDialect(tt.dialect)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.update, d.OnConflict(update))
+			assert.Equal(t, tt.ignore, d.OnConflict(nothing))
+		})
+	}
+}
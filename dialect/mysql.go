@@ -0,0 +1,163 @@
+package dialect
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-venus/venus/clause"
+	"github.com/go-venus/venus/schema"
+)
+
+type mysql struct{}
+
+var (
+	_ Dialect    = (*mysql)(nil)
+	_ Replacer   = (*mysql)(nil)
+	_ InsertIDer = (*mysql)(nil)
+)
+
+func init() {
+	RegisterDialect("mysql", &mysql{})
+}
+
+func (m *mysql) TableExistSQL(tableName string) (string, []any) {
+	return "SELECT table_name FROM information_schema.tables WHERE table_schema = DATABASE() AND table_name = ?", []any{tableName}
+}
+
+func (m *mysql) TablesSQL() (string, []any) {
+	return "SELECT table_name FROM information_schema.tables " +
+		"WHERE table_schema = DATABASE() AND table_type = 'BASE TABLE' ORDER BY table_name", nil
+}
+
+func (m *mysql) ColumnsSQL(tableName string) (string, []any) {
+	return "SELECT column_name, data_type, character_maximum_length FROM information_schema.columns " +
+		"WHERE table_schema = DATABASE() AND table_name = ? ORDER BY ordinal_position", []any{tableName}
+}
+
+func (m *mysql) IndexesSQL(tableName string) (string, []any) {
+	return "SELECT index_name, non_unique = 0, column_name FROM information_schema.statistics " +
+		"WHERE table_schema = DATABASE() AND table_name = ? ORDER BY index_name, seq_in_index", []any{tableName}
+}
+
+func (m *mysql) ModifyColumnSQL(tableName string, _ *schema.Field, definition string) string {
+	return fmt.Sprintf("ALTER TABLE %s MODIFY COLUMN %s", m.Quote(tableName), definition)
+}
+
+func (m *mysql) Quote(name string) string {
+	return quote(name, '`')
+}
+
+func (m *mysql) BindVar(int) string {
+	return "?"
+}
+
+func (m *mysql) AutoIncrement() string {
+	return "AUTO_INCREMENT"
+}
+
+func (m *mysql) ColumnComment(_, _, comment string) (string, string) {
+	return "COMMENT " + quoteString(comment), ""
+}
+
+func (m *mysql) DataTypeOf(field *schema.Field) string {
+	if field.ColumnType != "" {
+		return field.ColumnType
+	}
+
+	switch field.DataType {
+	case schema.Bool:
+		return "boolean"
+	case schema.Int, schema.Uint:
+		var dataType string
+		switch {
+		case field.Size <= 8:
+			dataType = "tinyint"
+		case field.Size <= 16:
+			dataType = "smallint"
+		case field.Size <= 32:
+			dataType = "int"
+		default:
+			dataType = "bigint"
+		}
+		if field.DataType == schema.Uint {
+			dataType += " unsigned"
+		}
+		return dataType
+	case schema.Float:
+		if field.Precision > 0 {
+			return fmt.Sprintf("decimal(%d, %d)", field.Precision, field.Scale)
+		}
+		if field.Size <= 32 {
+			return "float"
+		}
+		return "double"
+	case schema.String:
+		switch {
+		case field.Size <= 0:
+			return "varchar(255)"
+		case field.Size < 65536:
+			return fmt.Sprintf("varchar(%d)", field.Size)
+		}
+		return "longtext"
+	case schema.Time:
+		if field.Precision > 0 {
+			return fmt.Sprintf("datetime(%d)", field.Precision)
+		}
+		return "datetime(3)"
+	case schema.Bytes:
+		if field.Size > 0 && field.Size < 65536 {
+			return fmt.Sprintf("varbinary(%d)", field.Size)
+		}
+		return "longblob"
+	}
+
+	return string(field.DataType)
+}
+
+func (m *mysql) ReplaceInto() string {
+	return "REPLACE INTO"
+}
+
+// OnConflict updates a conflict column with itself to do nothing, mysql has no
+// conflict target and checks every unique key.
+func (m *mysql) OnConflict(conflict clause.OnConflict) string {
+	var assignments []string
+	if conflict.DoNothing {
+		column := m.Quote(conflict.Columns[0])
+		assignments = append(assignments, column+" = "+column)
+	}
+	for _, column := range conflict.DoUpdates {
+		column = m.Quote(column)
+		assignments = append(assignments, fmt.Sprintf("%s = VALUES(%s)", column, column))
+	}
+	return "ON DUPLICATE KEY UPDATE " + strings.Join(assignments, ", ")
+}
+
+// InsertIDs returns lastInsertID for single-row inserts only. mysql reports the key of
+// the first inserted row, the keys of the others are not consecutive when
+// auto_increment_increment is above 1 or with innodb_autoinc_lock_mode=2.
+func (m *mysql) InsertIDs(lastInsertID int64, n int) []int64 {
+	if n != 1 {
+		return nil
+	}
+	return []int64{lastInsertID}
+}
+
+// quote wraps every dot separated part of name with q, doubling any q inside it.
+func quote(name string, q byte) string {
+	parts := strings.Split(name, ".")
+	builder := strings.Builder{}
+	for i, part := range parts {
+		if i > 0 {
+			builder.WriteByte('.')
+		}
+		if part == "*" {
+			builder.WriteString(part)
+			continue
+		}
+		builder.WriteByte(q)
+		builder.WriteString(strings.ReplaceAll(part, string(q), string(q)+string(q)))
+		builder.WriteByte(q)
+	}
+	return builder.String()
+}
@@ -0,0 +1,132 @@
+package dialect
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/go-venus/venus/clause"
+	"github.com/go-venus/venus/schema"
+)
+
+type postgres struct{}
+
+var (
+	_ Dialect  = (*postgres)(nil)
+	_ Returner = (*postgres)(nil)
+)
+
+func init() {
+	RegisterDialect("postgres", &postgres{})
+	RegisterDialect("pgx", &postgres{})
+}
+
+func (p *postgres) TableExistSQL(tableName string) (string, []any) {
+	return "SELECT table_name FROM information_schema.tables WHERE table_schema = CURRENT_SCHEMA() AND table_name = ?", []any{tableName}
+}
+
+func (p *postgres) TablesSQL() (string, []any) {
+	return "SELECT table_name FROM information_schema.tables " +
+		"WHERE table_schema = CURRENT_SCHEMA() AND table_type = 'BASE TABLE' ORDER BY table_name", nil
+}
+
+func (p *postgres) ColumnsSQL(tableName string) (string, []any) {
+	return "SELECT column_name, data_type, character_maximum_length FROM information_schema.columns " +
+		"WHERE table_schema = CURRENT_SCHEMA() AND table_name = ? ORDER BY ordinal_position", []any{tableName}
+}
+
+func (p *postgres) IndexesSQL(tableName string) (string, []any) {
+	return "SELECT i.relname, ix.indisunique, a.attname FROM pg_index ix " +
+		"JOIN pg_class t ON t.oid = ix.indrelid JOIN pg_class i ON i.oid = ix.indexrelid " +
+		"JOIN pg_namespace n ON n.oid = t.relnamespace " +
+		"JOIN pg_attribute a ON a.attrelid = t.oid AND a.attnum = ANY(ix.indkey) " +
+		"WHERE n.nspname = CURRENT_SCHEMA() AND t.relname = ? " +
+		"ORDER BY i.relname, array_position(ix.indkey, a.attnum)", []any{tableName}
+}
+
+func (p *postgres) ModifyColumnSQL(tableName string, field *schema.Field, _ string) string {
+	return fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s TYPE %s", p.Quote(tableName), p.Quote(field.Name), p.DataTypeOf(field))
+}
+
+func (p *postgres) Quote(name string) string {
+	return quote(name, '"')
+}
+
+func (p *postgres) BindVar(n int) string {
+	return "$" + strconv.Itoa(n)
+}
+
+func (p *postgres) AutoIncrement() string {
+	return ""
+}
+
+func (p *postgres) ColumnComment(tableName, columnName, comment string) (string, string) {
+	return "", fmt.Sprintf("COMMENT ON COLUMN %s.%s IS %s", p.Quote(tableName), p.Quote(columnName), quoteString(comment))
+}
+
+func (p *postgres) DataTypeOf(field *schema.Field) string {
+	if field.ColumnType != "" {
+		return field.ColumnType
+	}
+
+	switch field.DataType {
+	case schema.Bool:
+		return "boolean"
+	case schema.Int, schema.Uint:
+		size := field.Size
+		// unsigned values need the next wider signed type
+		if field.DataType == schema.Uint && size < 64 {
+			size *= 2
+		}
+		if field.AutoIncrement {
+			switch {
+			case size <= 16:
+				return "smallserial"
+			case size <= 32:
+				return "serial"
+			}
+			return "bigserial"
+		}
+		switch {
+		case size <= 16:
+			return "smallint"
+		case size <= 32:
+			return "integer"
+		}
+		return "bigint"
+	case schema.Float:
+		if field.Precision > 0 {
+			return fmt.Sprintf("numeric(%d, %d)", field.Precision, field.Scale)
+		}
+		if field.Size <= 32 {
+			return "real"
+		}
+		return "double precision"
+	case schema.String:
+		if field.Size > 0 {
+			return fmt.Sprintf("varchar(%d)", field.Size)
+		}
+		return "text"
+	case schema.Time:
+		if field.Precision > 0 {
+			return fmt.Sprintf("timestamptz(%d)", field.Precision)
+		}
+		return "timestamptz"
+	case schema.Bytes:
+		return "bytea"
+	}
+
+	return string(field.DataType)
+}
+
+func (p *postgres) Returning(columns ...string) string {
+	quoted := make([]string, len(columns))
+	for i, column := range columns {
+		quoted[i] = p.Quote(column)
+	}
+	return "RETURNING " + strings.Join(quoted, ", ")
+}
+
+func (p *postgres) OnConflict(conflict clause.OnConflict) string {
+	return onConflict(p, conflict)
+}
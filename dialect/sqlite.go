@@ -0,0 +1,103 @@
+package dialect
+
+import (
+	"github.com/go-venus/venus/clause"
+	"github.com/go-venus/venus/schema"
+)
+
+type sqlite struct{}
+
+var (
+	_ Dialect    = (*sqlite)(nil)
+	_ Replacer   = (*sqlite)(nil)
+	_ InsertIDer = (*sqlite)(nil)
+)
+
+func init() {
+	RegisterDialect("sqlite3", &sqlite{})
+	RegisterDialect("sqlite", &sqlite{})
+}
+
+func (s *sqlite) TableExistSQL(tableName string) (string, []any) {
+	return "SELECT name FROM sqlite_master WHERE type = 'table' AND name = ?", []any{tableName}
+}
+
+func (s *sqlite) TablesSQL() (string, []any) {
+	return "SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite\\_%' ESCAPE '\\' ORDER BY name", nil
+}
+
+func (s *sqlite) ColumnsSQL(tableName string) (string, []any) {
+	return "SELECT name, type, NULL FROM pragma_table_info(?) ORDER BY cid", []any{tableName}
+}
+
+func (s *sqlite) IndexesSQL(tableName string) (string, []any) {
+	return "SELECT il.name, il.\"unique\", ii.name FROM pragma_index_list(?) il, pragma_index_info(il.name) ii " +
+		"ORDER BY il.name, ii.seqno", []any{tableName}
+}
+
+// ModifyColumnSQL returns "", sqlite cannot change the type of a column and does not
+// enforce sizes anyway.
+func (s *sqlite) ModifyColumnSQL(string, *schema.Field, string) string {
+	return ""
+}
+
+func (s *sqlite) Quote(name string) string {
+	return quote(name, '"')
+}
+
+func (s *sqlite) BindVar(int) string {
+	return "?"
+}
+
+func (s *sqlite) AutoIncrement() string {
+	return "AUTOINCREMENT"
+}
+
+func (s *sqlite) ColumnComment(_, _, _ string) (string, string) {
+	return "", ""
+}
+
+// DataTypeOf maps fields onto the sqlite type affinities, bool and time keep their
+// declared names so that drivers convert them back when scanning.
+func (s *sqlite) DataTypeOf(field *schema.Field) string {
+	if field.ColumnType != "" {
+		return field.ColumnType
+	}
+
+	switch field.DataType {
+	case schema.Bool:
+		return "boolean"
+	case schema.Int, schema.Uint:
+		return "integer"
+	case schema.Float:
+		if field.Precision > 0 {
+			return "numeric"
+		}
+		return "real"
+	case schema.String:
+		return "text"
+	case schema.Time:
+		return "datetime"
+	case schema.Bytes:
+		return "blob"
+	}
+
+	return string(field.DataType)
+}
+
+func (s *sqlite) ReplaceInto() string {
+	return "INSERT OR REPLACE INTO"
+}
+
+func (s *sqlite) OnConflict(conflict clause.OnConflict) string {
+	return onConflict(s, conflict)
+}
+
+// InsertIDs counts down to lastInsertID, sqlite reports the key of the last inserted row.
+func (s *sqlite) InsertIDs(lastInsertID int64, n int) []int64 {
+	ids := make([]int64, n)
+	for i := range ids {
+		ids[i] = lastInsertID - int64(n-1-i)
+	}
+	return ids
+}
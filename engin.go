@@ -1,36 +1,149 @@
 package venus
 
 import (
+	"context"
 	"database/sql"
+	"fmt"
+	"time"
 
 	"github.com/go-venus/venus/dialect"
+	"github.com/go-venus/venus/schema"
 	"github.com/go-venus/venus/session"
 )
 
+const (
+	defaultRetryInterval    = 100 * time.Millisecond
+	defaultRetryMaxInterval = 30 * time.Second
+)
+
 type Engine struct {
-	db      *sql.DB
-	dialect dialect.Dialect
+	db       *sql.DB
+	dialect  dialect.Dialect
+	registry *schema.Registry
 }
 
 func Open(config *Config) (e *Engine, err error) {
+	return OpenContext(context.Background(), config)
+}
+
+// OpenContext opens the database of config and pings it, retrying up to OpenRetries
+// times with an interval starting at OpenRetryInterval and doubling on every attempt.
+func OpenContext(ctx context.Context, config *Config) (e *Engine, err error) {
+	// make sure the specific dialect exists
+	dial, err := dialect.GetDialect(config.Driver)
+	if err != nil {
+		return
+	}
+
 	db, err := sql.Open(config.Driver, config.Source)
 	if err != nil {
 		return
 	}
+	config.applyPool(db)
+
 	// Send a ping to make sure the database connection is alive.
-	if err = db.Ping(); err != nil {
+	if err = ping(ctx, db, config); err != nil {
+		_ = db.Close()
 		return
 	}
-	// make sure the specific dialect exists
-	dial, err := dialect.GetDialect(config.Driver)
+
+	e = &Engine{db: db, dialect: dial, registry: schema.NewRegistry(config.NamingStrategy)}
+	return
+}
+
+// New adopts an opened database, using the dialect registered as dialectName and
+// the default naming strategy.
+func New(db *sql.DB, dialectName string) (*Engine, error) {
+	dial, err := dialect.GetDialect(dialectName)
 	if err != nil {
-		return
+		return nil, err
 	}
+	return &Engine{db: db, dialect: dial, registry: schema.NewRegistry(nil)}, nil
+}
 
-	e = &Engine{db: db, dialect: dial}
-	return
+func (c *Config) applyPool(db *sql.DB) {
+	if c.MaxOpenConns > 0 {
+		db.SetMaxOpenConns(c.MaxOpenConns)
+	}
+	if c.MaxIdleConns > 0 {
+		db.SetMaxIdleConns(c.MaxIdleConns)
+	}
+	if c.ConnMaxLifetime > 0 {
+		db.SetConnMaxLifetime(c.ConnMaxLifetime)
+	}
+	if c.ConnMaxIdleTime > 0 {
+		db.SetConnMaxIdleTime(c.ConnMaxIdleTime)
+	}
+}
+
+// ping pings db, retrying with exponential backoff while the database is not reachable.
+func ping(ctx context.Context, db *sql.DB, config *Config) (err error) {
+	interval := config.OpenRetryInterval
+	if interval <= 0 {
+		interval = defaultRetryInterval
+	}
+	maxInterval := config.OpenRetryMaxInterval
+	if maxInterval <= 0 {
+		maxInterval = defaultRetryMaxInterval
+	}
+
+	for attempt := 0; ; attempt++ {
+		if err = pingTimeout(ctx, db, config.PingTimeout); err == nil || attempt >= config.OpenRetries {
+			return
+		}
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("%w, last ping error: %s", ctx.Err(), err.Error())
+		case <-timer.C:
+		}
+		if interval *= 2; interval > maxInterval {
+			interval = maxInterval
+		}
+	}
+}
+
+func pingTimeout(ctx context.Context, db *sql.DB, timeout time.Duration) error {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	return db.PingContext(ctx)
+}
+
+// Close closes the database.
+func (e *Engine) Close() error {
+	return e.db.Close()
+}
+
+// Stats returns the statistics of the connection pool.
+func (e *Engine) Stats() sql.DBStats {
+	return e.db.Stats()
+}
+
+// Ping checks that the database is still reachable.
+func (e *Engine) Ping(ctx context.Context) error {
+	return e.db.PingContext(ctx)
+}
+
+// DB returns the underlying database handle.
+func (e *Engine) DB() *sql.DB {
+	return e.db
+}
+
+// Dialect returns the dialect of the database.
+func (e *Engine) Dialect() dialect.Dialect {
+	return e.dialect
+}
+
+// Registry returns the registry caching the tables parsed by the engine.
+func (e *Engine) Registry() *schema.Registry {
+	return e.registry
 }
 
 func NewSession[T any](e *Engine) *session.Session[T] {
-	return session.New[T](e.db, e.dialect)
+	return session.New[T](e.db, e.dialect, session.WithRegistry(e.registry))
 }
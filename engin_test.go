@@ -0,0 +1,84 @@
+package venus
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-venus/venus/schema"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpen(t *testing.T) {
+	e, err := Open(&Config{
+		Driver:       "sqlite3",
+		Source:       filepath.Join(t.TempDir(), "venus.db"),
+		MaxOpenConns: 3,
+		PingTimeout:  time.Second,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 3, e.Stats().MaxOpenConnections)
+	assert.NoError(t, e.Ping(context.Background()))
+
+	require.NoError(t, e.Close())
+	assert.Error(t, e.Ping(context.Background()))
+
+	_, err = Open(&Config{Driver: "unknown"})
+	assert.Error(t, err)
+}
+
+func TestOpenRetries(t *testing.T) {
+	config := &Config{
+		Driver:            "sqlite3",
+		Source:            filepath.Join(t.TempDir(), "missing", "venus.db"),
+		OpenRetries:       2,
+		OpenRetryInterval: 10 * time.Millisecond,
+	}
+
+	start := time.Now()
+	_, err := Open(config)
+	assert.Error(t, err)
+	// waits 10ms then 20ms
+	assert.GreaterOrEqual(t, time.Since(start), 30*time.Millisecond)
+
+	config.OpenRetries = 100
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err = OpenContext(ctx, config)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestNew(t *testing.T) {
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "venus.db"))
+	require.NoError(t, err)
+
+	e, err := New(db, "sqlite3")
+	require.NoError(t, err)
+	defer e.Close()
+	assert.Same(t, db, e.DB())
+	assert.NoError(t, e.Ping(context.Background()))
+
+	_, err = New(db, "unknown")
+	assert.Error(t, err)
+}
+
+func TestNamingStrategy(t *testing.T) {
+	type AuditLog struct {
+		CreatedAt time.Time
+	}
+	e, err := Open(&Config{
+		Driver:         "sqlite3",
+		Source:         filepath.Join(t.TempDir(), "venus.db"),
+		NamingStrategy: schema.NamingStrategy{TablePrefix: "app_", PluralTables: true},
+	})
+	require.NoError(t, err)
+	defer e.Close()
+
+	table := NewSession[AuditLog](e).RefTable()
+	assert.Equal(t, "app_audit_logs", table.TableName)
+	assert.Equal(t, []string{"created_at"}, table.FieldNames)
+}
@@ -0,0 +1,291 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/go-venus/venus/dialect"
+	"github.com/go-venus/venus/session"
+)
+
+var (
+	ErrDuplicateVersion = errors.New("duplicate migration version")
+	ErrUnknownVersion   = errors.New("unknown migration version")
+	ErrChecksumMismatch = errors.New("migration changed after it was applied")
+	ErrLocked           = errors.New("migrations are locked by another run")
+)
+
+// record is an applied migration.
+type record struct {
+	Version   int64     `venus:"column:version;PRIMARY KEY"`
+	Name      string    `venus:"column:name;NOT NULL"`
+	Checksum  string    `venus:"column:checksum;size:64;NOT NULL"`
+	AppliedAt time.Time `venus:"column:applied_at;NOT NULL"`
+}
+
+func (record) TableName() string {
+	return "venus_migrations"
+}
+
+// lock is the row held by the migrator applying or reverting migrations.
+type lock struct {
+	Id       int64     `venus:"column:id;PRIMARY KEY"`
+	LockedAt time.Time `venus:"column:locked_at;NOT NULL"`
+}
+
+func (lock) TableName() string {
+	return "venus_migrations_lock"
+}
+
+// Status is the state of a migration.
+type Status struct {
+	Version   int64
+	Name      string
+	Applied   bool
+	AppliedAt time.Time
+	Modified  bool // the migration changed after it was applied
+	Missing   bool // the migration was applied but is no longer known
+}
+
+type Migrator struct {
+	session    *session.Session[record]
+	locks      *session.Session[lock]
+	migrations []*Migration
+}
+
+// New returns a Migrator running migrations in version order.
+func New(db *sql.DB, dial dialect.Dialect, migrations ...*Migration) (*Migrator, error) {
+	sorted := append([]*Migration(nil), migrations...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Version < sorted[j].Version
+	})
+	for i := 1; i < len(sorted); i++ {
+		if sorted[i].Version == sorted[i-1].Version {
+			return nil, fmt.Errorf("%w: %d", ErrDuplicateVersion, sorted[i].Version)
+		}
+	}
+
+	return &Migrator{
+		session:    session.New[record](db, dial),
+		locks:      session.New[lock](db, dial),
+		migrations: sorted,
+	}, nil
+}
+
+// Up applies all pending migrations.
+func (m *Migrator) Up(ctx context.Context) error {
+	if len(m.migrations) == 0 {
+		return nil
+	}
+	return m.Goto(ctx, m.migrations[len(m.migrations)-1].Version)
+}
+
+// Down reverts the last n applied migrations.
+func (m *Migrator) Down(ctx context.Context, n int) error {
+	return m.locked(ctx, func() error {
+		return m.down(ctx, n)
+	})
+}
+
+func (m *Migrator) down(ctx context.Context, n int) error {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return err
+	}
+	if err = m.verify(applied); err != nil {
+		return err
+	}
+
+	for i := len(applied) - 1; i >= 0 && n > 0; i, n = i-1, n-1 {
+		migration, err := m.find(applied[i].Version)
+		if err != nil {
+			return err
+		}
+		if err = m.revert(ctx, migration); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Goto applies the pending migrations up to version and reverts the applied ones after it.
+func (m *Migrator) Goto(ctx context.Context, version int64) error {
+	if version != 0 {
+		if _, err := m.find(version); err != nil {
+			return err
+		}
+	}
+	return m.locked(ctx, func() error {
+		return m.goTo(ctx, version)
+	})
+}
+
+func (m *Migrator) goTo(ctx context.Context, version int64) error {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return err
+	}
+	if err = m.verify(applied); err != nil {
+		return err
+	}
+	done := make(map[int64]bool, len(applied))
+	for _, record := range applied {
+		done[record.Version] = true
+	}
+
+	for i := len(applied) - 1; i >= 0 && applied[i].Version > version; i-- {
+		migration, _ := m.find(applied[i].Version)
+		if err = m.revert(ctx, migration); err != nil {
+			return err
+		}
+	}
+	for _, migration := range m.migrations {
+		if migration.Version > version {
+			break
+		}
+		if done[migration.Version] {
+			continue
+		}
+		if err = m.apply(ctx, migration); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Status returns the state of every known and applied migration in version order.
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+	records := make(map[int64]record, len(applied))
+	for _, record := range applied {
+		records[record.Version] = record
+	}
+
+	var statuses []Status
+	for _, migration := range m.migrations {
+		status := Status{Version: migration.Version, Name: migration.Name}
+		if record, ok := records[migration.Version]; ok {
+			status.Applied = true
+			status.AppliedAt = record.AppliedAt
+			status.Modified = record.Checksum != migration.Checksum()
+			delete(records, migration.Version)
+		}
+		statuses = append(statuses, status)
+	}
+	for _, record := range records {
+		statuses = append(statuses, Status{
+			Version:   record.Version,
+			Name:      record.Name,
+			Applied:   true,
+			AppliedAt: record.AppliedAt,
+			Missing:   true,
+		})
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Version < statuses[j].Version
+	})
+	return statuses, nil
+}
+
+func (m *Migrator) apply(ctx context.Context, migration *Migration) error {
+	return m.session.TransactionContext(ctx, func(tx *session.Tx[record]) error {
+		if err := migration.up(ctx, executor{tx}); err != nil {
+			return fmt.Errorf("migrate up %d_%s: %w", migration.Version, migration.Name, err)
+		}
+		_, err := tx.InsertContext(ctx, record{
+			Version:   migration.Version,
+			Name:      migration.Name,
+			Checksum:  migration.Checksum(),
+			AppliedAt: time.Now().UTC(),
+		})
+		return err
+	})
+}
+
+func (m *Migrator) revert(ctx context.Context, migration *Migration) error {
+	return m.session.TransactionContext(ctx, func(tx *session.Tx[record]) error {
+		if err := migration.down(ctx, executor{tx}); err != nil {
+			return fmt.Errorf("migrate down %d_%s: %w", migration.Version, migration.Name, err)
+		}
+		_, err := tx.Where("version = ?", migration.Version).DeleteContext(ctx)
+		return err
+	})
+}
+
+// applied returns the applied migrations in version order, creating the table on first use.
+func (m *Migrator) applied(ctx context.Context) ([]record, error) {
+	if err := m.session.CreateTableContext(ctx, session.IfNotExists()); err != nil {
+		return nil, err
+	}
+	return m.session.OrderBy("version").SelectContext(ctx)
+}
+
+// verify checks that the applied migrations are known and unchanged.
+func (m *Migrator) verify(applied []record) error {
+	for _, record := range applied {
+		migration, err := m.find(record.Version)
+		if err != nil {
+			return err
+		}
+		if migration.Checksum() != record.Checksum {
+			return fmt.Errorf("%w: %d", ErrChecksumMismatch, record.Version)
+		}
+	}
+	return nil
+}
+
+// locked runs fn holding the migration lock, so that concurrent runs cannot apply
+// or revert the same migration twice.
+func (m *Migrator) locked(ctx context.Context, fn func() error) (err error) {
+	if err = m.locks.CreateTableContext(ctx, session.IfNotExists()); err != nil {
+		return
+	}
+	if _, err = m.locks.InsertContext(ctx, lock{Id: 1, LockedAt: time.Now().UTC()}); err != nil {
+		if n, countErr := m.locks.CountContext(ctx); countErr == nil && n > 0 {
+			err = ErrLocked
+		}
+		return
+	}
+	defer func() {
+		if unlockErr := m.Unlock(ctx); err == nil {
+			err = unlockErr
+		}
+	}()
+	return fn()
+}
+
+// Unlock releases the migration lock, such as one left behind by a run that crashed.
+func (m *Migrator) Unlock(ctx context.Context) error {
+	if err := m.locks.CreateTableContext(ctx, session.IfNotExists()); err != nil {
+		return err
+	}
+	_, err := m.locks.DeleteContext(ctx)
+	return err
+}
+
+func (m *Migrator) find(version int64) (*Migration, error) {
+	i := sort.Search(len(m.migrations), func(i int) bool {
+		return m.migrations[i].Version >= version
+	})
+	if i == len(m.migrations) || m.migrations[i].Version != version {
+		return nil, fmt.Errorf("%w: %d", ErrUnknownVersion, version)
+	}
+	return m.migrations[i], nil
+}
+
+// executor runs the statements of a migration through its transaction.
+type executor struct {
+	tx *session.Tx[record]
+}
+
+func (e executor) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	return e.tx.Raw(query, args...).ExecContext(ctx)
+}
@@ -0,0 +1,179 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/go-venus/venus/dialect"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var files = fstest.MapFS{
+	"migrations/1_create_users.up.sql":   {Data: []byte("CREATE TABLE users (id integer PRIMARY KEY, name text);\n-- seed\nINSERT INTO users (name) VALUES ('a;b');")},
+	"migrations/1_create_users.down.sql": {Data: []byte("DROP TABLE users;")},
+	"migrations/2_add_email.up.sql":      {Data: []byte("ALTER TABLE users ADD COLUMN email text;")},
+	"migrations/2_add_email.down.sql":    {Data: []byte("ALTER TABLE users DROP COLUMN email;")},
+	"migrations/README.md":               {Data: []byte("ignored")},
+}
+
+func newMigrator(t *testing.T, db *sql.DB, migrations ...*Migration) *Migrator {
+	t.Helper()
+	loaded, err := Load(files, "migrations")
+	require.NoError(t, err)
+
+	dial, _ := dialect.GetDialect("sqlite3")
+	m, err := New(db, dial, append(loaded, migrations...)...)
+	require.NoError(t, err)
+	return m
+}
+
+func openDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "venus.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+	return db
+}
+
+func versions(t *testing.T, m *Migrator) (applied []int64) {
+	t.Helper()
+	statuses, err := m.Status(context.Background())
+	require.NoError(t, err)
+	for _, status := range statuses {
+		if status.Applied {
+			applied = append(applied, status.Version)
+		}
+	}
+	return
+}
+
+func TestMigrator(t *testing.T) {
+	ctx := context.Background()
+	db := openDB(t)
+	m := newMigrator(t, db, &Migration{
+		Version: 3,
+		Name:    "seed_admin",
+		Up: func(ctx context.Context, tx Executor) error {
+			_, err := tx.ExecContext(ctx, "INSERT INTO users (name, email) VALUES (?, ?)", "admin", "admin@example.com")
+			return err
+		},
+		Down: func(ctx context.Context, tx Executor) error {
+			_, err := tx.ExecContext(ctx, "DELETE FROM users WHERE name = ?", "admin")
+			return err
+		},
+	})
+
+	require.NoError(t, m.Up(ctx))
+	assert.Equal(t, []int64{1, 2, 3}, versions(t, m))
+
+	var count int
+	require.NoError(t, db.QueryRow("SELECT count(*) FROM users").Scan(&count))
+	assert.Equal(t, 2, count)
+
+	require.NoError(t, m.Down(ctx, 2))
+	assert.Equal(t, []int64{1}, versions(t, m))
+
+	require.NoError(t, m.Goto(ctx, 2))
+	assert.Equal(t, []int64{1, 2}, versions(t, m))
+
+	require.NoError(t, m.Goto(ctx, 0))
+	assert.Empty(t, versions(t, m))
+
+	assert.ErrorIs(t, m.Goto(ctx, 42), ErrUnknownVersion)
+}
+
+func TestMigratorRollback(t *testing.T) {
+	ctx := context.Background()
+	errBroken := errors.New("broken")
+	m := newMigrator(t, openDB(t), &Migration{
+		Version: 3,
+		Name:    "broken",
+		Up: func(ctx context.Context, tx Executor) error {
+			if _, err := tx.ExecContext(ctx, "INSERT INTO users (name) VALUES (?)", "ghost"); err != nil {
+				return err
+			}
+			return errBroken
+		},
+	})
+
+	assert.ErrorIs(t, m.Up(ctx), errBroken)
+	assert.Equal(t, []int64{1, 2}, versions(t, m))
+}
+
+func TestMigratorChecksum(t *testing.T) {
+	ctx := context.Background()
+	db := openDB(t)
+	require.NoError(t, newMigrator(t, db).Up(ctx))
+
+	files["migrations/2_add_email.up.sql"] = &fstest.MapFile{Data: []byte("ALTER TABLE users ADD COLUMN mail text;")}
+	defer func() {
+		files["migrations/2_add_email.up.sql"] = &fstest.MapFile{Data: []byte("ALTER TABLE users ADD COLUMN email text;")}
+	}()
+
+	m := newMigrator(t, db)
+	statuses, err := m.Status(ctx)
+	require.NoError(t, err)
+	assert.False(t, statuses[0].Modified)
+	assert.True(t, statuses[1].Modified)
+	assert.ErrorIs(t, m.Up(ctx), ErrChecksumMismatch)
+	assert.ErrorIs(t, m.Down(ctx, 1), ErrChecksumMismatch)
+	assert.Equal(t, []int64{1, 2}, versions(t, m))
+}
+
+func TestMigratorLock(t *testing.T) {
+	ctx := context.Background()
+	db := openDB(t)
+	m := newMigrator(t, db)
+
+	var blocked error
+	other := newMigrator(t, db, &Migration{
+		Version: 3,
+		Name:    "concurrent",
+		Up: func(ctx context.Context, tx Executor) error {
+			blocked = m.Up(ctx)
+			return nil
+		},
+	})
+	// m runs while other holds the lock
+	require.NoError(t, other.Up(ctx))
+	assert.ErrorIs(t, blocked, ErrLocked)
+
+	_, err := other.locks.Insert(lock{Id: 1})
+	require.NoError(t, err)
+	assert.ErrorIs(t, m.Down(ctx, 1), ErrLocked)
+	require.NoError(t, m.Unlock(ctx))
+	require.NoError(t, other.Down(ctx, 1))
+	assert.Equal(t, []int64{1, 2}, versions(t, m))
+}
+
+func TestNewDuplicateVersion(t *testing.T) {
+	dial, _ := dialect.GetDialect("sqlite3")
+	_, err := New(nil, dial, &Migration{Version: 1}, &Migration{Version: 1})
+	assert.ErrorIs(t, err, ErrDuplicateVersion)
+}
+
+func TestSplitStatements(t *testing.T) {
+	script := `
+-- create
+CREATE TABLE t (v text DEFAULT ';');
+/* ; */ INSERT INTO t VALUES ("a;b");
+CREATE FUNCTION f() RETURNS int AS $$ BEGIN RETURN 1; END; $$ LANGUAGE plpgsql;
+CREATE FUNCTION g() RETURNS text AS $body$ BEGIN RETURN '$$;'; END; $body$ LANGUAGE plpgsql;
+SELECT a$b; SELECT $1;
+-- trailing comment;
+`
+	assert.Equal(t, []string{
+		"-- create\nCREATE TABLE t (v text DEFAULT ';')",
+		`/* ; */ INSERT INTO t VALUES ("a;b")`,
+		"CREATE FUNCTION f() RETURNS int AS $$ BEGIN RETURN 1; END; $$ LANGUAGE plpgsql",
+		"CREATE FUNCTION g() RETURNS text AS $body$ BEGIN RETURN '$$;'; END; $body$ LANGUAGE plpgsql",
+		"SELECT a$b",
+		"SELECT $1",
+	}, splitStatements(script))
+}
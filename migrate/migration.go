@@ -0,0 +1,173 @@
+package migrate
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Executor executes the statements of a migration inside its transaction.
+type Executor interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// Func is a migration written in go.
+type Func func(ctx context.Context, tx Executor) error
+
+// Migration is a versioned schema change. Up and Down take precedence over UpSQL
+// and DownSQL, which may hold several statements separated by semicolons.
+type Migration struct {
+	Version int64
+	Name    string
+	Up      Func
+	Down    Func
+	UpSQL   string
+	DownSQL string
+}
+
+// Checksum returns the sha256 of the SQL scripts, "" for go migrations whose
+// changes cannot be detected.
+func (m *Migration) Checksum() string {
+	if m.UpSQL == "" && m.DownSQL == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(m.UpSQL + "\x00" + m.DownSQL))
+	return hex.EncodeToString(sum[:])
+}
+
+func (m *Migration) up(ctx context.Context, tx Executor) error {
+	if m.Up != nil {
+		return m.Up(ctx, tx)
+	}
+	return execScript(ctx, tx, m.UpSQL)
+}
+
+func (m *Migration) down(ctx context.Context, tx Executor) error {
+	if m.Down != nil {
+		return m.Down(ctx, tx)
+	}
+	return execScript(ctx, tx, m.DownSQL)
+}
+
+func execScript(ctx context.Context, tx Executor, script string) error {
+	for _, stmt := range splitStatements(script) {
+		if _, err := tx.ExecContext(ctx, stmt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// dollarQuote matches the opening $$ or $tag$ of a dollar quoted body.
+var dollarQuote = regexp.MustCompile(`^\$([A-Za-z_][A-Za-z0-9_]*)?\$`)
+
+// splitStatements splits script on the semicolons that are not part of a quoted
+// string, a comment or a $$ or $tag$ quoted body.
+func splitStatements(script string) []string {
+	var (
+		stmts []string
+		start int
+		end   string // the delimiter closing the current quote or comment
+	)
+	for i := 0; i < len(script); i++ {
+		if end != "" {
+			if strings.HasPrefix(script[i:], end) {
+				i += len(end) - 1
+				end = ""
+			}
+			continue
+		}
+
+		switch {
+		case script[i] == '\'' || script[i] == '"' || script[i] == '`':
+			end = script[i : i+1]
+		case strings.HasPrefix(script[i:], "--"):
+			end = "\n"
+		case strings.HasPrefix(script[i:], "/*"):
+			end = "*/"
+			i++
+		case script[i] == '$' && (i == 0 || !isIdentByte(script[i-1])) && dollarQuote.MatchString(script[i:]):
+			end = dollarQuote.FindString(script[i:])
+			i += len(end) - 1
+		case script[i] == ';':
+			stmts = appendStatement(stmts, script[start:i])
+			start = i + 1
+		}
+	}
+	return appendStatement(stmts, script[start:])
+}
+
+// isIdentByte reports whether c may be part of an identifier, which can hold $
+// in postgres.
+func isIdentByte(c byte) bool {
+	return c == '_' || c == '$' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9'
+}
+
+func appendStatement(stmts []string, stmt string) []string {
+	if stmt = strings.TrimSpace(stmt); stmt != "" && !isComment(stmt) {
+		stmts = append(stmts, stmt)
+	}
+	return stmts
+}
+
+// isComment reports whether stmt only holds line comments.
+func isComment(stmt string) bool {
+	for _, line := range strings.Split(stmt, "\n") {
+		if line = strings.TrimSpace(line); line != "" && !strings.HasPrefix(line, "--") {
+			return false
+		}
+	}
+	return true
+}
+
+var fileNamePattern = regexp.MustCompile(`^(\d+)_(.+)\.(up|down)\.sql$`)
+
+// Load reads the migrations of dir in fsys, named as <version>_<name>.up.sql
+// and <version>_<name>.down.sql.
+func Load(fsys fs.FS, dir string) ([]*Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	versions := map[int64]*Migration{}
+	var migrations []*Migration
+	for _, entry := range entries {
+		matches := fileNamePattern.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || matches == nil {
+			continue
+		}
+
+		version, err := strconv.ParseInt(matches[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version of %s: %w", entry.Name(), err)
+		}
+		content, err := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		migration, ok := versions[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: matches[2]}
+			versions[version] = migration
+			migrations = append(migrations, migration)
+		} else if migration.Name != matches[2] {
+			return nil, fmt.Errorf("%w: %d", ErrDuplicateVersion, version)
+		}
+
+		if matches[3] == "up" {
+			migration.UpSQL = string(content)
+		} else {
+			migration.DownSQL = string(content)
+		}
+	}
+	return migrations, nil
+}
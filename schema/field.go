@@ -1,16 +1,146 @@
 package schema
 
 import (
+	"database/sql"
 	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/go-venus/venus/util"
 )
 
 type DataType string
 
+const (
+	Bool   DataType = "bool"
+	Int    DataType = "int"
+	Uint   DataType = "uint"
+	Float  DataType = "float"
+	String DataType = "string"
+	Time   DataType = "time"
+	Bytes  DataType = "bytes"
+)
+
 type Field struct {
 	StructName  string
+	StructIndex []int // 字段在模型中的索引路径, 含嵌入结构体
 	Name        string
 	FieldType   reflect.Type
 	StructField reflect.StructField
 	Tag         *Tag
 	Table       *Table
+	DataType    DataType // 通用类型
+	ColumnType  string   // TYPE标签声明的原始列类型, 由方言原样使用
+	Size        int
+	Precision   int
+	Scale       int
+
+	PrimaryKey    bool
+	AutoIncrement bool
+	NotNull       bool
+	Unique        bool
+	HasDefault    bool
+	DefaultValue  string // 原样写入DDL的默认值
+	Comment       string
+}
+
+// ReflectValueOf returns the field in the struct value v following StructIndex. Nil
+// embedded pointers on the way are allocated when v is addressable, otherwise the
+// returned value is invalid.
+func (f *Field) ReflectValueOf(v reflect.Value) reflect.Value {
+	for i, index := range f.StructIndex {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				if !v.CanSet() {
+					return reflect.Value{}
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(index)
+	}
+	return v
+}
+
+// nullTypes maps the sql.Null* wrappers onto the type they hold.
+var nullTypes = map[reflect.Type]reflect.Type{
+	reflect.TypeOf(sql.NullBool{}):    reflect.TypeOf(false),
+	reflect.TypeOf(sql.NullByte{}):    reflect.TypeOf(byte(0)),
+	reflect.TypeOf(sql.NullInt16{}):   reflect.TypeOf(int16(0)),
+	reflect.TypeOf(sql.NullInt32{}):   reflect.TypeOf(int32(0)),
+	reflect.TypeOf(sql.NullInt64{}):   reflect.TypeOf(int64(0)),
+	reflect.TypeOf(sql.NullFloat64{}): reflect.TypeOf(float64(0)),
+	reflect.TypeOf(sql.NullString{}):  reflect.TypeOf(""),
+	reflect.TypeOf(sql.NullTime{}):    reflect.TypeOf(time.Time{}),
+}
+
+// parseDataType sets DataType, Size, Precision and Scale from the go type and the
+// SIZE, PRECISION and SCALE tag settings, and ColumnType from the TYPE tag setting.
+func (f *Field) parseDataType() {
+	typ := f.FieldType
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if t, ok := nullTypes[typ]; ok {
+		typ = t
+	}
+
+	switch typ.Kind() {
+	case reflect.Bool:
+		f.DataType = Bool
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		f.DataType = Int
+		f.Size = typ.Bits()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		f.DataType = Uint
+		f.Size = typ.Bits()
+	case reflect.Float32, reflect.Float64:
+		f.DataType = Float
+		f.Size = typ.Bits()
+	case reflect.String:
+		f.DataType = String
+	case reflect.Slice, reflect.Array:
+		if typ.Elem().Kind() == reflect.Uint8 {
+			f.DataType = Bytes
+		}
+	case reflect.Struct:
+		if typ == reflect.TypeOf(time.Time{}) {
+			f.DataType = Time
+		}
+	}
+
+	settings := f.Tag.TagSettings
+	if size, err := strconv.Atoi(settings["SIZE"]); err == nil {
+		f.Size = size
+	}
+	if precision, err := strconv.Atoi(settings["PRECISION"]); err == nil {
+		f.Precision = precision
+	}
+	if scale, err := strconv.Atoi(settings["SCALE"]); err == nil {
+		f.Scale = scale
+	}
+	if typ, ok := settings["TYPE"]; ok && typ != "TYPE" {
+		f.ColumnType = typ
+	}
+}
+
+// parseConstraints sets the column constraints declared in the tag settings.
+func (f *Field) parseConstraints() {
+	settings := f.Tag.TagSettings
+	f.PrimaryKey = hasSetting(settings, "PRIMARY KEY", "PRIMARYKEY", "PRIMARY_KEY")
+	f.AutoIncrement = hasSetting(settings, "AUTOINCREMENT", "AUTO_INCREMENT")
+	f.NotNull = hasSetting(settings, "NOT NULL", "NOTNULL", "NOT_NULL")
+	f.Unique = hasSetting(settings, "UNIQUE")
+	f.DefaultValue, f.HasDefault = settings["DEFAULT"]
+	f.Comment = settings["COMMENT"]
+}
+
+func hasSetting(settings map[string]string, names ...string) bool {
+	for _, name := range names {
+		if value, ok := settings[name]; ok {
+			return util.CheckTruth(value)
+		}
+	}
+	return false
 }
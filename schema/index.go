@@ -0,0 +1,49 @@
+package schema
+
+// Index is an index declared with the index or uniqueIndex tag, fields sharing
+// the same index name make up a composite index.
+type Index struct {
+	Name   string
+	Unique bool
+	Fields []*Field
+	named  bool // 是否在标签中指定了索引名
+}
+
+// NameOn returns the name of the index on tableName, unnamed indexes are named
+// after the table so that tables sharing the model get distinct index names.
+func (i *Index) NameOn(tableName string) string {
+	if i.named {
+		return i.Name
+	}
+	return defaultIndexName(tableName, i.Fields[0])
+}
+
+func defaultIndexName(tableName string, field *Field) string {
+	return "idx_" + tableName + "_" + field.Name
+}
+
+// parseIndexes collects the indexes declared on the fields of the table.
+func (s *Table) parseIndexes() {
+	indexes := map[string]*Index{}
+	for _, field := range s.Fields {
+		settings := field.Tag.TagSettings
+		for _, key := range []string{"INDEX", "UNIQUEINDEX", "UNIQUE_INDEX"} {
+			name, ok := settings[key]
+			if !ok {
+				continue
+			}
+			named := name != key
+			if !named {
+				name = defaultIndexName(s.TableName, field)
+			}
+
+			index, ok := indexes[name]
+			if !ok {
+				index = &Index{Name: name, Unique: key != "INDEX", named: named}
+				indexes[name] = index
+				s.Indexes = append(s.Indexes, index)
+			}
+			index.Fields = append(index.Fields, field)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package schema
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Namer derives the table and column names of models.
+type Namer interface {
+	TableName(structName string) string
+	ColumnName(tableName, fieldName string) string
+}
+
+// NamingStrategy is the default Namer, converting names to snake_case.
+type NamingStrategy struct {
+	TablePrefix  string // 表名前缀
+	PluralTables bool   // 表名使用复数, User -> users
+	// TableNameFunc and ColumnNameFunc replace the snake_case conversion, the
+	// prefix and pluralization still apply to table names.
+	TableNameFunc  func(structName string) string
+	ColumnNameFunc func(fieldName string) string
+}
+
+var DefaultNamingStrategy Namer = NamingStrategy{}
+
+func (ns NamingStrategy) TableName(structName string) string {
+	name := SnakeCase(structName)
+	if ns.TableNameFunc != nil {
+		name = ns.TableNameFunc(structName)
+	}
+	if ns.PluralTables {
+		name = Plural(name)
+	}
+	return ns.TablePrefix + name
+}
+
+func (ns NamingStrategy) ColumnName(_, fieldName string) string {
+	if ns.ColumnNameFunc != nil {
+		return ns.ColumnNameFunc(fieldName)
+	}
+	return SnakeCase(fieldName)
+}
+
+// SnakeCase converts a go identifier to snake_case, keeping initialisms together:
+// CreatedAt -> created_at, UserID -> user_id, HTTPServer -> http_server.
+func SnakeCase(name string) string {
+	runes := []rune(name)
+	builder := strings.Builder{}
+	builder.Grow(len(name) + 4)
+	for i, r := range runes {
+		if unicode.IsUpper(r) && i > 0 {
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				builder.WriteByte('_')
+			}
+		}
+		builder.WriteRune(unicode.ToLower(r))
+	}
+	return builder.String()
+}
+
+var irregularPlurals = map[string]string{
+	"person": "people",
+	"child":  "children",
+	"man":    "men",
+	"woman":  "women",
+	"data":   "data",
+	"info":   "info",
+}
+
+// Plural returns the English plural of the last word of a snake_case name.
+func Plural(name string) string {
+	prefix, word := "", name
+	if i := strings.LastIndexByte(name, '_'); i >= 0 {
+		prefix, word = name[:i+1], name[i+1:]
+	}
+
+	if plural, ok := irregularPlurals[word]; ok {
+		return prefix + plural
+	}
+	switch {
+	case word == "":
+		return name
+	case strings.HasSuffix(word, "y") && len(word) > 1 && !strings.ContainsRune("aeiou", rune(word[len(word)-2])):
+		word = word[:len(word)-1] + "ies"
+	case strings.HasSuffix(word, "s") || strings.HasSuffix(word, "x") || strings.HasSuffix(word, "z") ||
+		strings.HasSuffix(word, "ch") || strings.HasSuffix(word, "sh"):
+		word += "es"
+	default:
+		word += "s"
+	}
+	return prefix + word
+}
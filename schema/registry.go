@@ -0,0 +1,61 @@
+package schema
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// DefaultRegistry parses models with the default naming strategy.
+var DefaultRegistry = NewRegistry(DefaultNamingStrategy)
+
+// Registry caches the tables parsed from models by their type. Engines own a
+// registry so that engines naming tables differently do not share tables.
+type Registry struct {
+	namer  Namer
+	rw     sync.RWMutex
+	tables map[reflect.Type]*Table
+}
+
+// NewRegistry returns a Registry naming tables and columns with namer.
+func NewRegistry(namer Namer) *Registry {
+	if namer == nil {
+		namer = DefaultNamingStrategy
+	}
+	return &Registry{
+		namer:  namer,
+		tables: map[reflect.Type]*Table{},
+	}
+}
+
+// Namer returns the naming strategy of the registry.
+func (r *Registry) Namer() Namer {
+	return r.namer
+}
+
+// Parse returns the table of model, a struct or a pointer to one, parsing it on first use.
+func (r *Registry) Parse(model any) (*Table, error) {
+	modelType := reflect.TypeOf(model)
+	for modelType != nil && modelType.Kind() == reflect.Ptr {
+		modelType = modelType.Elem()
+	}
+	if modelType == nil || modelType.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("unsupported model %T, want a struct", model)
+	}
+
+	r.rw.RLock()
+	table, ok := r.tables[modelType]
+	r.rw.RUnlock()
+	if ok {
+		return table, nil
+	}
+
+	r.rw.Lock()
+	defer r.rw.Unlock()
+	// another goroutine may have parsed it while waiting for the lock
+	if table, ok = r.tables[modelType]; !ok {
+		table = parse(model, modelType, r.namer)
+		r.tables[modelType] = table
+	}
+	return table, nil
+}
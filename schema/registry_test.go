@@ -0,0 +1,76 @@
+package schema
+
+import (
+	"sync"
+	"testing"
+)
+
+func mustParse(t *testing.T, r *Registry, model any) *Table {
+	t.Helper()
+	table, err := r.Parse(model)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return table
+}
+
+func TestRegistryKeyedByType(t *testing.T) {
+	r := NewRegistry(nil)
+	first := func() *Table {
+		type Account struct{ Name string }
+		return mustParse(t, r, Account{})
+	}()
+	second := func() *Table {
+		type Account struct{ Email string }
+		return mustParse(t, r, &Account{})
+	}()
+
+	if first == second || first.FieldNames[0] != "name" || second.FieldNames[0] != "email" {
+		t.Fatalf("types named alike share a table: %v %v", first.FieldNames, second.FieldNames)
+	}
+
+	type Account struct{ Name string }
+	if mustParse(t, r, Account{}) != mustParse(t, r, &Account{}) {
+		t.Fatal("a struct and its pointer must share a table")
+	}
+}
+
+func TestRegistryNamers(t *testing.T) {
+	type Account struct{ Name string }
+	plain := mustParse(t, NewRegistry(nil), Account{})
+	plural := mustParse(t, NewRegistry(NamingStrategy{PluralTables: true}), Account{})
+	if plain.TableName != "account" || plural.TableName != "accounts" {
+		t.Fatalf("registries share tables: %s %s", plain.TableName, plural.TableName)
+	}
+}
+
+func TestRegistryConcurrent(t *testing.T) {
+	type Account struct{ Name string }
+	r := NewRegistry(nil)
+
+	tables := make([]*Table, 16)
+	var wg sync.WaitGroup
+	for i := range tables {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			tables[i], _ = r.Parse(&Account{})
+		}(i)
+	}
+	wg.Wait()
+
+	for _, table := range tables {
+		if table != tables[0] {
+			t.Fatal("parsed the same type twice")
+		}
+	}
+}
+
+func TestRegistryUnsupported(t *testing.T) {
+	r := NewRegistry(nil)
+	for _, model := range []any{nil, 1, new(string), []struct{}{}} {
+		if _, err := r.Parse(model); err == nil {
+			t.Fatalf("parsed %T", model)
+		}
+	}
+}
@@ -1,17 +1,10 @@
 package schema
 
 import (
+	"database/sql"
 	"reflect"
 	"strings"
-	"sync"
-
-	"golang.org/x/sync/singleflight"
-)
-
-var (
-	singleFlight = singleflight.Group{}
-	tableCache   = map[string]*Table{}
-	rwTableCache = sync.RWMutex{}
+	"time"
 )
 
 type TableName = string
@@ -22,68 +15,164 @@ type Table struct {
 	Fields           []*Field  // 字段
 	FieldNames       []string  // 字段名(列名)
 	StructFieldNames []string
-	fieldMap         map[string] /*字段名(列名)*/ *Field
+	Indexes          []*Index
+	PrimaryFields    []*Field // 主键字段, 复合主键按声明顺序
+	// AutoIncrementField is the integer field generated by the database on insert, the
+	// single integer primary key tagged as such. Its AutoIncrement is set for the DDL to
+	// declare it generated whether it was tagged auto increment or not.
+	AutoIncrementField *Field
+	fieldMap           map[string] /*字段名(列名)*/ *Field
 }
 
 func (s *Table) GetField(name string) *Field {
 	return s.fieldMap[name]
 }
 
+// IsPrimaryField reports whether field is one of the primary key fields of the table,
+// tagged or found by convention.
+func (s *Table) IsPrimaryField(field *Field) bool {
+	for _, primary := range s.PrimaryFields {
+		if primary == field {
+			return true
+		}
+	}
+	return false
+}
+
+// Parse parses model with the default registry, it panics when model is not a struct.
 func Parse[T any](model T) *Table {
-	modelType := reflect.Indirect(reflect.ValueOf(model)).Type()
-	tableName := strings.ToLower(modelType.Name())
-	table, _, _ := singleFlight.Do(tableName, func() (interface{}, error) {
-		rwTableCache.RLock()
-		table, ok := tableCache[tableName]
-		rwTableCache.RUnlock()
-		if ok {
-			return table, nil
+	table, err := DefaultRegistry.Parse(model)
+	if err != nil {
+		panic(err)
+	}
+	return table
+}
+
+// Tabler is implemented by models naming their own table.
+type Tabler interface {
+	TableName() string
+}
+
+// parse parses the struct type of model, naming its table and columns with namer.
+// Models implementing Tabler keep the name they return.
+func parse(model any, modelType reflect.Type, namer Namer) *Table {
+	var tableName string
+	if tabler, ok := reflect.New(modelType).Interface().(Tabler); ok {
+		tableName = tabler.TableName()
+	} else {
+		tableName = namer.TableName(modelType.Name())
+	}
+
+	table := &Table{
+		Model:     model,
+		TableName: tableName,
+		fieldMap:  make(map[string]*Field),
+	}
+
+	table.parseFields(modelType, nil, "", namer)
+	table.parsePrimaryFields()
+	table.parseIndexes()
+
+	return table
+}
+
+// parseFields adds the fields of the struct type typ found at the index path of the
+// model, flattening anonymous structs and the struct fields tagged embedded. The
+// embedded tag of other fields is ignored.
+func (s *Table) parseFields(typ reflect.Type, index []int, prefix string, namer Namer) {
+	for i := 0; i < typ.NumField(); i++ {
+		p := typ.Field(i)
+		tag := ParseTag(p.Tag, ";")
+		if _, ok := tag.TagSettings["-"]; ok || (!p.Anonymous && !p.IsExported()) {
+			continue
 		}
 
-		table = &Table{
-			Model:     model,
-			TableName: tableName,
-			fieldMap:  make(map[string]*Field),
+		structIndex := append(append([]int(nil), index...), i)
+		if _, ok := tag.TagSettings["EMBEDDED"]; (ok || p.Anonymous) && isStruct(p.Type) {
+			fieldType := p.Type
+			if fieldType.Kind() == reflect.Ptr {
+				fieldType = fieldType.Elem()
+			}
+			s.parseFields(fieldType, structIndex, prefix+tag.TagSettings["EMBEDDEDPREFIX"], namer)
+			continue
+		}
+		if !p.IsExported() {
+			continue
 		}
 
-		numField := modelType.NumField()
-		for i := 0; i < numField; i++ {
-			p := modelType.Field(i)
-			if !p.Anonymous && p.IsExported() {
-				field := &Field{
-					StructName: p.Name,
-					Table:      table,
-					FieldType:  p.Type,
-				}
-
-				if _, ok := p.Tag.Lookup("venus"); ok {
-					field.Tag = ParseTag(p.Tag, ";")
-				}
-
-				var fieldName string
-				if name, ok := field.Tag.TagSettings["COLUMN"]; ok {
-					fieldName = name
-				} else {
-					fieldName = strings.ToLower(p.Name)
-				}
-
-				field.Name = fieldName
-				table.Fields = append(table.Fields, field)
-				table.FieldNames = append(table.FieldNames, fieldName)
-				table.StructFieldNames = append(table.StructFieldNames, p.Name)
-				table.fieldMap[fieldName] = field
+		field := &Field{
+			StructName:  p.Name,
+			StructIndex: structIndex,
+			Table:       s,
+			FieldType:   p.Type,
+			StructField: p,
+			Tag:         tag,
+		}
 
+		if name, ok := field.Tag.TagSettings["COLUMN"]; ok {
+			field.Name = prefix + name
+		} else {
+			field.Name = prefix + namer.ColumnName(s.TableName, p.Name)
+		}
+
+		field.parseDataType()
+		field.parseConstraints()
+		s.Fields = append(s.Fields, field)
+		s.FieldNames = append(s.FieldNames, field.Name)
+		s.StructFieldNames = append(s.StructFieldNames, p.Name)
+		s.fieldMap[field.Name] = field
+	}
+}
+
+// parsePrimaryFields records the fields tagged as primary key, the field with the
+// column id is the primary key of models tagging none, and the auto increment field.
+// The id convention only applies to the statements, the field is not declared as
+// primary key in the DDL.
+func (s *Table) parsePrimaryFields() {
+	for _, field := range s.Fields {
+		if field.PrimaryKey {
+			s.PrimaryFields = append(s.PrimaryFields, field)
+		}
+	}
+	if len(s.PrimaryFields) == 0 {
+		for _, field := range s.Fields {
+			if strings.EqualFold(field.Name, "id") {
+				s.PrimaryFields = append(s.PrimaryFields, field)
+				break
 			}
 		}
-		rwTableCache.RLock()
-		tableCache[tableName] = table
-		rwTableCache.RUnlock()
+	}
 
-		return table, nil
+	// the database does not generate the id of models following the convention, nor
+	// the fields of a composite primary key
+	if len(s.PrimaryFields) == 1 && s.PrimaryFields[0].PrimaryKey && isInteger(s.PrimaryFields[0].FieldType) {
+		s.AutoIncrementField = s.PrimaryFields[0]
+		s.AutoIncrementField.AutoIncrement = true
+	}
+}
 
-	})
+// isInteger reports whether typ is a signed or unsigned integer type.
+func isInteger(typ reflect.Type) bool {
+	switch typ.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	}
+	return false
+}
 
-	return table.(*Table)
+var (
+	timeType    = reflect.TypeOf(time.Time{})
+	scannerType = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+)
+
+// isStruct reports whether typ is a struct, or a pointer to one, holding columns
+// rather than being the value of a single column.
+func isStruct(typ reflect.Type) bool {
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	return typ.Kind() == reflect.Struct && typ != timeType && !reflect.PtrTo(typ).Implements(scannerType)
 }
 
 func (s *Table) RecordValues(dest interface{}) []interface{} {
@@ -91,8 +180,27 @@ func (s *Table) RecordValues(dest interface{}) []interface{} {
 
 	var fieldValues []interface{}
 	for _, field := range s.Fields {
-		fieldValues = append(fieldValues, destValue.FieldByName(field.StructName).Interface())
+		if value := field.ReflectValueOf(destValue); value.IsValid() {
+			fieldValues = append(fieldValues, value.Interface())
+		} else {
+			fieldValues = append(fieldValues, nil)
+		}
 	}
 
 	return fieldValues
 }
+
+// PrimaryValues returns the values of the primary key fields of dest.
+func (s *Table) PrimaryValues(dest interface{}) []interface{} {
+	destValue := reflect.Indirect(reflect.ValueOf(dest))
+
+	var values []interface{}
+	for _, field := range s.PrimaryFields {
+		if value := field.ReflectValueOf(destValue); value.IsValid() {
+			values = append(values, value.Interface())
+		} else {
+			values = append(values, nil)
+		}
+	}
+	return values
+}
@@ -1,6 +1,8 @@
 package schema
 
 import (
+	"reflect"
+	"strings"
 	"testing"
 	"time"
 )
@@ -18,10 +20,208 @@ type User struct {
 func TestParse(t *testing.T) {
 
 	schema := Parse(&User{})
-	if schema.TableName != "User" || len(schema.Fields) != 2 {
+	if schema.TableName != "user" || len(schema.Fields) != 3 {
 		t.Fatal("failed to parse User struct")
 	}
-	if schema.GetField("StructName").Tag.Tag != `venus:"PRIMARY KEY"` {
+	if id := schema.GetField("id"); id == nil || len(id.StructIndex) != 2 {
+		t.Fatal("failed to parse embedded Model")
+	}
+	if schema.GetField("name").Tag.Tag != `venus:"PRIMARY KEY"` {
+		t.Fatal("failed to parse primary key")
+	}
+	if !schema.GetField("name").PrimaryKey || schema.GetField("age").PrimaryKey {
 		t.Fatal("failed to parse primary key")
 	}
 }
+
+func TestParseDataType(t *testing.T) {
+	type Product struct {
+		Code    string  `venus:"size:32"`
+		Price   float64 `venus:"precision:10;scale:2"`
+		Stock   *uint32
+		Payload string `venus:"type:json"`
+	}
+
+	schema := Parse(&Product{})
+	code := schema.GetField("code")
+	if code.DataType != String || code.Size != 32 {
+		t.Fatalf("failed to parse code: %s %d", code.DataType, code.Size)
+	}
+	price := schema.GetField("price")
+	if price.DataType != Float || price.Precision != 10 || price.Scale != 2 {
+		t.Fatalf("failed to parse price: %s %d %d", price.DataType, price.Precision, price.Scale)
+	}
+	stock := schema.GetField("stock")
+	if stock.DataType != Uint || stock.Size != 32 {
+		t.Fatalf("failed to parse stock: %s %d", stock.DataType, stock.Size)
+	}
+	if payload := schema.GetField("payload"); payload.DataType != String || payload.ColumnType != "json" {
+		t.Fatalf("failed to parse payload: %s %s", payload.DataType, payload.ColumnType)
+	}
+}
+
+func TestParseIndexes(t *testing.T) {
+	type Visit struct {
+		Path    string `venus:"index"`
+		Visitor int64  `venus:"index:idx_visit_visitor_day"`
+		Day     string `venus:"index:idx_visit_visitor_day"`
+		Token   string `venus:"uniqueIndex"`
+	}
+
+	indexes := Parse(&Visit{}).Indexes
+	if len(indexes) != 3 {
+		t.Fatalf("failed to parse indexes: %d", len(indexes))
+	}
+	if indexes[0].Name != "idx_visit_path" || indexes[0].Unique {
+		t.Fatalf("failed to parse index: %s", indexes[0].Name)
+	}
+	if indexes[1].Name != "idx_visit_visitor_day" || len(indexes[1].Fields) != 2 {
+		t.Fatalf("failed to parse composite index: %s", indexes[1].Name)
+	}
+	if indexes[2].Name != "idx_visit_token" || !indexes[2].Unique {
+		t.Fatalf("failed to parse unique index: %s", indexes[2].Name)
+	}
+}
+
+func TestNamingStrategy(t *testing.T) {
+	names := map[string]string{
+		"CreatedAt":  "created_at",
+		"ID":         "id",
+		"UserID":     "user_id",
+		"HTTPServer": "http_server",
+		"Int32P":     "int32_p",
+		"name":       "name",
+	}
+	for name, want := range names {
+		if got := SnakeCase(name); got != want {
+			t.Fatalf("SnakeCase(%q) = %q, want %q", name, got, want)
+		}
+	}
+
+	plurals := map[string]string{
+		"user":       "users",
+		"category":   "categories",
+		"day":        "days",
+		"address":    "addresses",
+		"box":        "boxes",
+		"order_item": "order_items",
+		"person":     "people",
+	}
+	for name, want := range plurals {
+		if got := Plural(name); got != want {
+			t.Fatalf("Plural(%q) = %q, want %q", name, got, want)
+		}
+	}
+
+	type BlogPost struct {
+		PostID    int64
+		CreatedAt time.Time
+	}
+	ns := NamingStrategy{TablePrefix: "t_", PluralTables: true}
+	table := mustParse(t, NewRegistry(ns), &BlogPost{})
+	if table.TableName != "t_blog_posts" || table.FieldNames[0] != "post_id" || table.FieldNames[1] != "created_at" {
+		t.Fatalf("failed to name BlogPost: %s %v", table.TableName, table.FieldNames)
+	}
+
+	ns = NamingStrategy{TableNameFunc: strings.ToUpper, ColumnNameFunc: strings.ToUpper}
+	if got := ns.TableName("Comment"); got != "COMMENT" {
+		t.Fatalf("failed to name Comment: %s", got)
+	}
+	if got := ns.ColumnName("COMMENT", "Body"); got != "BODY" {
+		t.Fatalf("failed to name Body: %s", got)
+	}
+}
+
+type Invoice struct {
+	Number string
+}
+
+func (Invoice) TableName() string {
+	return "billing_invoice"
+}
+
+func TestParseTabler(t *testing.T) {
+	ns := NamingStrategy{TablePrefix: "t_", PluralTables: true}
+	if table := mustParse(t, NewRegistry(ns), &Invoice{}); table.TableName != "billing_invoice" {
+		t.Fatalf("failed to name Invoice: %s", table.TableName)
+	}
+}
+
+type Address struct {
+	City   string
+	Street string
+}
+
+type Customer struct {
+	*Model
+	Name     string
+	Home     Address `venus:"embedded;embeddedPrefix:home_"`
+	Work     Address `venus:"embedded;embeddedPrefix:work_"`
+	Password string  `venus:"-"`
+}
+
+func TestParseEmbedded(t *testing.T) {
+	table := Parse(&Customer{})
+	want := []string{"id", "name", "home_city", "home_street", "work_city", "work_street"}
+	if strings.Join(table.FieldNames, ",") != strings.Join(want, ",") {
+		t.Fatalf("failed to flatten Customer: %v", table.FieldNames)
+	}
+
+	values := table.RecordValues(Customer{Name: "Tom", Work: Address{City: "Paris"}})
+	if values[0] != nil || values[1] != "Tom" || values[4] != "Paris" {
+		t.Fatalf("failed to read record values: %v", values)
+	}
+
+	var customer Customer
+	table.GetField("id").ReflectValueOf(reflect.ValueOf(&customer).Elem()).SetInt(7)
+	table.GetField("home_city").ReflectValueOf(reflect.ValueOf(&customer).Elem()).SetString("Rome")
+	if customer.Model == nil || customer.Id != 7 || customer.Home.City != "Rome" {
+		t.Fatalf("failed to set embedded fields: %+v", customer)
+	}
+}
+
+func TestParseEmbeddedNotStruct(t *testing.T) {
+	type Tagged struct {
+		Id   int64
+		Name string `venus:"embedded"`
+	}
+
+	table := Parse(&Tagged{})
+	if strings.Join(table.FieldNames, ",") != "id,name" {
+		t.Fatalf("failed to ignore embedded on a column: %v", table.FieldNames)
+	}
+}
+
+type Enrollment struct {
+	StudentId int64 `venus:"PRIMARY KEY"`
+	CourseId  int64 `venus:"PRIMARY KEY"`
+	Grade     string
+}
+
+func TestParsePrimaryFields(t *testing.T) {
+	if fields := Parse(&User{}).PrimaryFields; len(fields) != 1 || fields[0].Name != "name" {
+		t.Fatal("failed to parse tagged primary key")
+	}
+
+	customer := Parse(&Customer{})
+	if fields := customer.PrimaryFields; len(fields) != 1 || fields[0].Name != "id" || fields[0].PrimaryKey {
+		t.Fatal("failed to default primary key to id")
+	}
+	if customer.AutoIncrementField != nil {
+		t.Fatal("unexpected auto increment field of the default primary key")
+	}
+	if values := customer.PrimaryValues(&Customer{Model: &Model{Id: 3}}); len(values) != 1 || values[0] != 3 {
+		t.Fatalf("failed to read primary values: %v", values)
+	}
+
+	enrollment := Parse(&Enrollment{})
+	if fields := enrollment.PrimaryFields; len(fields) != 2 || fields[0].Name != "student_id" || fields[1].Name != "course_id" {
+		t.Fatal("failed to parse composite primary key")
+	}
+	if enrollment.AutoIncrementField != nil {
+		t.Fatal("unexpected auto increment field of composite primary key")
+	}
+	if fields := Parse(&Address{}).PrimaryFields; len(fields) != 0 {
+		t.Fatal("unexpected primary key")
+	}
+}
@@ -0,0 +1,70 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// BatchError reports the chunk of InsertInBatches that failed.
+type BatchError struct {
+	Batch  int // 失败批次的序号, 从0开始
+	Offset int // 失败批次第一个值在values中的下标
+	Size   int // 失败批次的值个数
+	Err    error
+}
+
+func (e *BatchError) Error() string {
+	return fmt.Sprintf("insert batch %d (values %d-%d): %s", e.Batch, e.Offset, e.Offset+e.Size-1, e.Err)
+}
+
+func (e *BatchError) Unwrap() error {
+	return e.Err
+}
+
+// InsertInBatches inserts values with one statement per batchSize values, keeping under
+// the placeholder and packet limits of the database. The batches run in one transaction,
+// started and committed here unless d is already a transaction. On failure the error is
+// a *BatchError and rowsAffected counts the rows of the batches before it, which are
+// rolled back when the transaction was started here. rowsAffected is 0 when the commit
+// fails.
+func (d *DB[T]) InsertInBatches(ctx context.Context, values []T, batchSize int) (rowsAffected int64, err error) {
+	if batchSize <= 0 {
+		batchSize = len(values)
+	}
+	if d.tx != nil {
+		return d.insertBatches(ctx, values, batchSize)
+	}
+
+	err = d.transaction(ctx, func(tx *Tx[T]) (err error) {
+		d.Clear()
+		rowsAffected, err = tx.insertBatches(ctx, values, batchSize)
+		return
+	})
+	var batchErr *BatchError
+	if err != nil && !errors.As(err, &batchErr) {
+		rowsAffected = 0
+	}
+	return
+}
+
+func (d *DB[T]) insertBatches(ctx context.Context, values []T, batchSize int) (rowsAffected int64, err error) {
+	defer d.holdTableName()()
+
+	// every Insert clears the columns chosen with Columns and Omit
+	selects, omits := d.selects, d.omits
+	for batch, offset := 0, 0; offset < len(values); batch, offset = batch+1, offset+batchSize {
+		end := offset + batchSize
+		if end > len(values) {
+			end = len(values)
+		}
+
+		d.selects, d.omits = selects, omits
+		n, err := d.InsertContext(ctx, values[offset:end]...)
+		if err != nil {
+			return rowsAffected, &BatchError{Batch: batch, Offset: offset, Size: end - offset, Err: err}
+		}
+		rowsAffected += n
+	}
+	return
+}
@@ -0,0 +1,87 @@
+package session
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-venus/venus/dialect"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInsertInBatches(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer db.Close()
+
+	products := []Product{{Id: 1, Code: "a"}, {Id: 2, Code: "b"}, {Id: 3, Code: "c"}}
+	mysql, _ := dialect.GetDialect("mysql")
+	s := New[Product](db, mysql)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO `product` (`id`,`code`) VALUES (?, ?), (?, ?)").
+		WithArgs(int64(1), "a", int64(2), "b").
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec("INSERT INTO `product` (`id`,`code`) VALUES (?, ?)").
+		WithArgs(int64(3), "c").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	n, err := s.Omit("price").InsertInBatches(context.Background(), products, 2)
+	require.NoError(t, err)
+	assert.EqualValues(t, 3, n)
+
+	failure := errors.New("max_allowed_packet exceeded")
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO `product` (`id`,`code`,`price`) VALUES (?, ?, ?), (?, ?, ?)").
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec("INSERT INTO `product` (`id`,`code`,`price`) VALUES (?, ?, ?)").
+		WillReturnError(failure)
+	mock.ExpectRollback()
+
+	n, err = s.InsertInBatches(context.Background(), products, 2)
+	assert.EqualValues(t, 2, n)
+	assert.ErrorIs(t, err, failure)
+	var batchErr *BatchError
+	require.ErrorAs(t, err, &batchErr)
+	assert.Equal(t, 1, batchErr.Batch)
+	assert.Equal(t, 2, batchErr.Offset)
+	assert.EqualError(t, err, "insert batch 1 (values 2-2): max_allowed_packet exceeded")
+
+	// nothing is kept when the commit fails
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO `product` (`id`,`code`,`price`) VALUES (?, ?, ?), (?, ?, ?), (?, ?, ?)").
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectCommit().WillReturnError(sql.ErrConnDone)
+
+	n, err = s.InsertInBatches(context.Background(), products, 0)
+	assert.Zero(t, n)
+	assert.ErrorIs(t, err, sql.ErrConnDone)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSQLiteInsertInBatches(t *testing.T) {
+	s := newSQLiteSession[Account](t)
+
+	now := time.Now().UTC().Truncate(time.Second)
+	var accounts []Account
+	for i := 1; i <= 25; i++ {
+		accounts = append(accounts, Account{Id: int64(i), Name: fmt.Sprint("user", i), CreatedAt: now})
+	}
+
+	err := s.Transaction(func(tx *Tx[Account]) error {
+		n, err := tx.InsertInBatches(context.Background(), accounts, 10)
+		assert.EqualValues(t, 25, n)
+		return err
+	})
+	require.NoError(t, err)
+
+	count, err := s.Count()
+	require.NoError(t, err)
+	assert.EqualValues(t, 25, count)
+}
@@ -0,0 +1,68 @@
+package session
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-venus/venus/dialect"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestColumns(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectExec("INSERT INTO `product` (`id`,`code`) VALUES (?, ?)").
+		WithArgs(int64(1), "a").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("UPDATE `product` SET `code` = ?, `price` = ? WHERE id = ?").
+		WithArgs("b", 2.5, 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE `product` SET `price` = ? WHERE id = ?").
+		WithArgs(0.0, 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("SELECT `id`,`price` FROM `product`").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "price"}).AddRow(1, 0.0))
+
+	mysql, _ := dialect.GetDialect("mysql")
+	s := New[Product](db, mysql)
+
+	_, err = s.Omit("price").Insert(Product{Id: 1, Code: "a", Price: 1.5})
+	require.NoError(t, err)
+
+	// zero fields and the primary key are skipped
+	_, err = s.Where("id = ?", 1).Updates(Product{Id: 1, Code: "b", Price: 2.5})
+	require.NoError(t, err)
+
+	_, err = s.Where("id = ?", 1).Columns("price").Updates(Product{Code: "c"})
+	require.NoError(t, err)
+
+	products, err := s.Columns("id", "price").Select()
+	require.NoError(t, err)
+	assert.Equal(t, []Product{{Id: 1}}, products)
+
+	_, err = s.Update(map[string]interface{}{"name": "a"})
+	assert.ErrorIs(t, err, ErrInvalidColumn)
+	_, err = s.Omit("name").Select()
+	assert.ErrorIs(t, err, ErrInvalidColumn)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSQLiteColumns(t *testing.T) {
+	s := newSQLiteSession[Account](t)
+
+	now := time.Now().UTC().Truncate(time.Second)
+	_, err := s.Insert(Account{Id: 1, Name: "Tom", Age: 18, Active: true, CreatedAt: now})
+	require.NoError(t, err)
+
+	n, err := s.Where("id = ?", 1).Omit("age").Update(map[string]interface{}{"name": "Sam", "age": 30})
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, n)
+
+	account, err := s.FindByID(1)
+	require.NoError(t, err)
+	assert.Equal(t, Account{Id: 1, Name: "Sam", Age: 18, Active: true, CreatedAt: now}, account)
+}
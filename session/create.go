@@ -0,0 +1,141 @@
+package session
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/go-venus/venus/clause"
+	"github.com/go-venus/venus/dialect"
+	"github.com/go-venus/venus/schema"
+)
+
+// Create inserts values like Insert, then writes the keys generated by the database
+// back into the auto increment field of every value whose field is zero. On mysql the
+// keys are only written back by single-row inserts. Values mixing zero and set keys
+// are rejected with ErrMixedKeys.
+func (d *DB[T]) Create(values ...*T) (int64, error) {
+	return d.CreateContext(context.Background(), values...)
+}
+
+func (d *DB[T]) CreateContext(ctx context.Context, values ...*T) (rowsAffected int64, err error) {
+	table := d.RefTable()
+	field := table.AutoIncrementField
+	var zero int
+	if field != nil {
+		zero = zeroFields(field, values)
+	}
+	if zero > 0 && zero < len(values) {
+		d.Clear()
+		return 0, ErrMixedKeys
+	}
+	if zero == 0 {
+		records := make([]T, len(values))
+		for i, value := range values {
+			records[i] = *value
+		}
+		return d.InsertContext(ctx, records...)
+	}
+
+	fields, err := d.fields()
+	if err != nil {
+		return
+	}
+	if beforeInsert, ok := table.Model.(BeforeInsert[T]); ok {
+		if err = beforeInsert.BeforeInsert(ctx, d); err != nil {
+			return
+		}
+	}
+
+	// the auto increment column is left out for the database to generate it
+	var columns []*schema.Field
+	for _, f := range fields {
+		if f != field {
+			columns = append(columns, f)
+		}
+	}
+	recordValues := make([]interface{}, 0, len(values))
+	for _, value := range values {
+		recordValues = append(recordValues, fieldValues(columns, value))
+	}
+
+	d.Clause.Set(clause.Insert, d.quotedTableName(), d.quoteColumns(fieldNames(columns)))
+	d.Clause.Set(clause.Values, recordValues...)
+	if returner, ok := d.dialect.(dialect.Returner); ok {
+		d.Clause.Set(clause.Returning, returner.Returning(field.Name))
+		rowsAffected, err = d.createReturning(ctx, field, values)
+	} else {
+		rowsAffected, err = d.createLastInsertID(ctx, field, values)
+	}
+	if err != nil {
+		return
+	}
+
+	if afterInsert, ok := table.Model.(AfterInsert[T]); ok {
+		err = afterInsert.AfterInsert(ctx, d)
+	}
+	return
+}
+
+// createReturning runs the insert reading the generated keys from its RETURNING rows.
+func (d *DB[T]) createReturning(ctx context.Context, field *schema.Field, values []*T) (rowsAffected int64, err error) {
+	sqlStr, vars := d.Clause.Build(clause.Insert, clause.Values, clause.Returning)
+	rows, err := d.raw(sqlStr, vars...).QueryRowsContext(ctx)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		if int(rowsAffected) < len(values) {
+			if err = rows.Scan(scanDest(field, reflect.ValueOf(values[rowsAffected]).Elem())); err != nil {
+				return
+			}
+		}
+		rowsAffected++
+	}
+	err = rows.Err()
+	return
+}
+
+// createLastInsertID runs the insert deriving the generated keys from LastInsertId.
+func (d *DB[T]) createLastInsertID(ctx context.Context, field *schema.Field, values []*T) (rowsAffected int64, err error) {
+	sqlStr, vars := d.Clause.Build(clause.Insert, clause.Values)
+	result, err := d.raw(sqlStr, vars...).ExecContext(ctx)
+	if err != nil {
+		return
+	}
+	if rowsAffected, err = result.RowsAffected(); err != nil {
+		return
+	}
+
+	insertIDer, ok := d.dialect.(dialect.InsertIDer)
+	if !ok {
+		return
+	}
+	lastInsertID, err := result.LastInsertId()
+	if err != nil {
+		return
+	}
+	for i, id := range insertIDer.InsertIDs(lastInsertID, len(values)) {
+		dest := field.ReflectValueOf(reflect.ValueOf(values[i]).Elem())
+		if !dest.IsValid() {
+			continue
+		}
+		if dest.CanInt() {
+			dest.SetInt(id)
+		} else {
+			dest.SetUint(uint64(id))
+		}
+	}
+	return
+}
+
+// zeroFields reports whether field is zero in every value.
+func zeroFields[T any](field *schema.Field, values []*T) (n int) {
+	for _, value := range values {
+		if v := field.ReflectValueOf(reflect.ValueOf(value).Elem()); !v.IsValid() || v.IsZero() {
+			n++
+		}
+	}
+	return
+}
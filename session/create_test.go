@@ -0,0 +1,131 @@
+package session
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-venus/venus/clause"
+	"github.com/go-venus/venus/dialect"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateLastInsertID(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectExec("INSERT INTO `product` (`code`,`price`) VALUES (?, ?)").
+		WithArgs("a", 1.5).
+		WillReturnResult(sqlmock.NewResult(10, 1))
+	mock.ExpectExec("INSERT INTO `product` (`code`,`price`) VALUES (?, ?), (?, ?)").
+		WithArgs("b", 2.5, "c", 3.5).
+		WillReturnResult(sqlmock.NewResult(11, 2))
+
+	mysql, _ := dialect.GetDialect("mysql")
+	s := New[Product](db, mysql)
+	product := &Product{Code: "a", Price: 1.5}
+	n, err := s.Create(product)
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, n)
+	assert.EqualValues(t, 10, product.Id)
+
+	// the keys of a multi-row insert are not consecutive on every mysql setup
+	products := []*Product{{Code: "b", Price: 2.5}, {Code: "c", Price: 3.5}}
+	n, err = s.Create(products...)
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, n)
+	assert.Zero(t, products[0].Id)
+	assert.Zero(t, products[1].Id)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreateReturning(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery(`INSERT INTO "product" ("code","price") VALUES ($1, $2), ($3, $4) RETURNING "id"`).
+		WithArgs("a", 1.5, "b", 2.5).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7).AddRow(9))
+
+	pg, _ := dialect.GetDialect("postgres")
+	products := []*Product{{Code: "a", Price: 1.5}, {Code: "b", Price: 2.5}}
+	n, err := New[Product](db, pg).Create(products...)
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, n)
+	assert.EqualValues(t, 7, products[0].Id)
+	assert.EqualValues(t, 9, products[1].Id)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSQLiteCreate(t *testing.T) {
+	s := newSQLiteSession[Account](t)
+
+	now := time.Now().UTC().Truncate(time.Second)
+	_, err := s.Insert(Account{Id: 5, Name: "Tom", CreatedAt: now})
+	require.NoError(t, err)
+
+	accounts := []*Account{{Name: "Sam", CreatedAt: now}, {Name: "Bob", CreatedAt: now}}
+	n, err := s.Create(accounts...)
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, n)
+	assert.EqualValues(t, 6, accounts[0].Id)
+	assert.EqualValues(t, 7, accounts[1].Id)
+
+	account, err := s.FindByID(accounts[1].Id)
+	require.NoError(t, err)
+	assert.Equal(t, "Bob", account.Name)
+
+	// keys set by the caller are inserted as they are
+	_, err = s.Create(&Account{Id: 10, Name: "Ann", CreatedAt: now})
+	require.NoError(t, err)
+	_, err = s.FindByID(10)
+	assert.NoError(t, err)
+
+	_, err = s.Create(&Account{Id: 20, Name: "Eve", CreatedAt: now}, &Account{Name: "Kim", CreatedAt: now})
+	assert.ErrorIs(t, err, ErrMixedKeys)
+	count, err := s.Count()
+	require.NoError(t, err)
+	assert.EqualValues(t, 4, count)
+}
+
+func TestUpsert(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectExec("INSERT INTO `product` (`id`,`code`,`price`) VALUES (?, ?, ?) ON DUPLICATE KEY UPDATE `price` = VALUES(`price`)").
+		WithArgs(int64(1), "a", 1.5).
+		WillReturnResult(sqlmock.NewResult(1, 2))
+
+	mysql, _ := dialect.GetDialect("mysql")
+	n, err := New[Product](db, mysql).Upsert(clause.OnConflict{Columns: []string{"code"}, UpdateAll: true}, Product{Id: 1, Code: "a", Price: 1.5})
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, n)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	_, err = New[struct{ Name string }](db, mysql).Upsert(clause.OnConflict{DoNothing: true}, struct{ Name string }{})
+	assert.ErrorIs(t, err, ErrMissingPrimaryKey)
+}
+
+func TestSQLiteUpsert(t *testing.T) {
+	s := newSQLiteSession[Product](t)
+
+	_, err := s.Insert(Product{Id: 1, Code: "a", Price: 1.5})
+	require.NoError(t, err)
+
+	_, err = s.Upsert(clause.OnConflict{DoNothing: true}, Product{Id: 1, Code: "b", Price: 9})
+	require.NoError(t, err)
+	product, err := s.FindByID(1)
+	require.NoError(t, err)
+	assert.Equal(t, Product{Id: 1, Code: "a", Price: 1.5}, product)
+
+	_, err = s.Upsert(clause.OnConflict{Columns: []string{"code"}, DoUpdates: []string{"price"}},
+		Product{Id: 2, Code: "a", Price: 2.5}, Product{Id: 3, Code: "c", Price: 3})
+	require.NoError(t, err)
+	products, err := s.OrderBy("id").Select()
+	require.NoError(t, err)
+	assert.Equal(t, []Product{{Id: 1, Code: "a", Price: 2.5}, {Id: 3, Code: "c", Price: 3}}, products)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
 	"reflect"
 	"strings"
 
@@ -12,7 +13,14 @@ import (
 	"github.com/go-venus/venus/schema"
 )
 
-var ErrNotFound = errors.New("not found")
+var (
+	ErrNotFound          = errors.New("not found")
+	ErrMissingPrimaryKey = errors.New("missing primary key")
+	ErrInvalidColumn     = errors.New("invalid column")
+	ErrZeroPrimaryKey    = errors.New("zero primary key not generated by the database")
+	ErrMixedKeys         = errors.New("zero and set auto increment keys in one insert")
+	ErrGrouped           = errors.New("aggregate of grouped rows, use Scan")
+)
 
 type (
 	db interface {
@@ -25,15 +33,27 @@ type (
 	}
 
 	DB[T any] struct {
-		model    T
-		DestType reflect.Value
-		db       *sql.DB
-		tx       *sql.Tx
-		Sql      strings.Builder
-		SqlVars  []any
-		dialect  dialect.Dialect
-		refTable *schema.Table
-		Clause   clause.Clause
+		model     T
+		DestType  reflect.Value
+		db        *sql.DB
+		tx        *sql.Tx
+		Sql       strings.Builder
+		SqlVars   []any
+		dialect   dialect.Dialect
+		refTable  *schema.Table
+		table     string            // 覆盖模型表名, 见Table
+		holdTable bool              // 执行多条语句时保留table, 见holdTableName
+		selects   []string          // 写入和查询的列, 见Columns
+		omits     []string          // 排除的列, 见Omit
+		where     clause.Expression // 累积的查询条件, 见Where和Or
+		joins     []interface{}     // 连接的表, 见Joins
+		groups    []string          // 分组的列, 见GroupBy
+		having    clause.Expression // 分组的过滤条件, 见Having
+		projects  []string          // Scan查询的列, 见Project
+		track     bool              // Select记录快照, 见Track
+		registry  *schema.Registry
+		snapshots *snapshots
+		Clause    clause.Clause
 	}
 	Session[T any] struct {
 		*DB[T]
@@ -44,10 +64,34 @@ type (
 	}
 )
 
-func New[T any](db *sql.DB, dialect dialect.Dialect) *Session[T] {
-	d := &DB[T]{db: db, dialect: dialect}
+// Option configures a Session.
+type Option func(*options)
+
+type options struct {
+	registry *schema.Registry
+}
+
+// WithRegistry parses the model with registry instead of schema.DefaultRegistry.
+func WithRegistry(registry *schema.Registry) Option {
+	return func(o *options) {
+		o.registry = registry
+	}
+}
+
+// New returns a Session of the model T, it panics when T is not a struct.
+func New[T any](db *sql.DB, dialect dialect.Dialect, opts ...Option) *Session[T] {
+	o := options{registry: schema.DefaultRegistry}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	d := &DB[T]{db: db, dialect: dialect, snapshots: newSnapshots(nil), registry: o.registry}
 	d.DestType = reflect.Indirect(reflect.ValueOf(d.model))
-	d.refTable = schema.Parse(d.model)
+	refTable, err := o.registry.Parse(d.model)
+	if err != nil {
+		panic(err)
+	}
+	d.refTable = refTable
 	return &Session[T]{
 		DB: d,
 	}
@@ -58,22 +102,100 @@ func (d *DB[T]) Insert(values ...T) (int64, error) {
 }
 
 func (d *DB[T]) InsertContext(ctx context.Context, values ...T) (rowsAffected int64, err error) {
+	fields, err := d.fields()
+	if err != nil {
+		return
+	}
+	d.Clause.Set(clause.Insert, d.quotedTableName(), d.quoteColumns(fieldNames(fields)))
+	return d.insertContext(ctx, clause.Insert, fields, values...)
+}
+
+// Replace inserts values, replacing the rows they conflict with.
+func (d *DB[T]) Replace(values ...T) (int64, error) {
+	return d.ReplaceContext(context.Background(), values...)
+}
+
+func (d *DB[T]) ReplaceContext(ctx context.Context, values ...T) (rowsAffected int64, err error) {
+	replacer, ok := d.dialect.(dialect.Replacer)
+	if !ok {
+		return 0, dialect.ErrNotSupported
+	}
+
+	fields, err := d.fields()
+	if err != nil {
+		return
+	}
+	d.snapshots.drop(d.tableName())
+	d.Clause.Set(clause.Replace, replacer.ReplaceInto(), d.quotedTableName(), d.quoteColumns(fieldNames(fields)))
+	return d.insertContext(ctx, clause.Replace, fields, values...)
+}
+
+// Upsert inserts values, handling the rows conflicting with existing ones as conflict
+// describes. The conflict columns default to the primary key, and a conflict updating
+// no column does nothing. UpdateAll updates every column but the conflict columns and
+// the primary key.
+func (d *DB[T]) Upsert(conflict clause.OnConflict, values ...T) (int64, error) {
+	return d.UpsertContext(context.Background(), conflict, values...)
+}
+
+func (d *DB[T]) UpsertContext(ctx context.Context, conflict clause.OnConflict, values ...T) (rowsAffected int64, err error) {
 	table := d.RefTable()
-	if afterInsert, ok := table.Model.(AfterInsert[T]); ok {
-		if err = afterInsert.AfterInsert(ctx, d); err != nil {
+	if len(conflict.Columns) == 0 {
+		if len(table.PrimaryFields) == 0 {
+			return 0, ErrMissingPrimaryKey
+		}
+		for _, field := range table.PrimaryFields {
+			conflict.Columns = append(conflict.Columns, field.Name)
+		}
+	}
+
+	if conflict.UpdateAll {
+		conflict.DoUpdates = nil
+		for _, field := range table.Fields {
+			if !table.IsPrimaryField(field) && !contains(conflict.Columns, field.Name) {
+				conflict.DoUpdates = append(conflict.DoUpdates, field.Name)
+			}
+		}
+	}
+	if conflict.DoNothing || len(conflict.DoUpdates) == 0 {
+		conflict.DoNothing, conflict.DoUpdates = true, nil
+	}
+
+	fields, err := d.fields()
+	if err != nil {
+		return
+	}
+	d.snapshots.drop(d.tableName())
+	d.Clause.Set(clause.Insert, d.quotedTableName(), d.quoteColumns(fieldNames(fields)))
+	d.Clause.Set(clause.Conflict, d.dialect.OnConflict(conflict))
+	return d.insertContext(ctx, clause.Insert, fields, values...)
+}
+
+func contains(s []string, v string) bool {
+	for _, e := range s {
+		if e == v {
+			return true
+		}
+	}
+	return false
+}
+
+func (d *DB[T]) insertContext(ctx context.Context, insertType clause.Type, fields []*schema.Field, values ...T) (rowsAffected int64, err error) {
+	table := d.RefTable()
+	if beforeInsert, ok := table.Model.(BeforeInsert[T]); ok {
+		if err = beforeInsert.BeforeInsert(ctx, d); err != nil {
 			return
 		}
 	}
 
 	recordValues := make([]interface{}, 0)
 	for _, value := range values {
-		d.Clause.Set(clause.Insert, table.TableName, table.FieldNames)
-		recordValues = append(recordValues, table.RecordValues(value))
+		recordValues = append(recordValues, fieldValues(fields, value))
 	}
 
 	d.Clause.Set(clause.Values, recordValues...)
-	sqlStr, vars := d.Clause.Build(clause.Insert, clause.Values)
-	result, err := d.Raw(sqlStr, vars...).ExecContext(ctx)
+	sqlStr, vars := d.Clause.Build(insertType, clause.Values, clause.Conflict)
+	result, err := d.raw(sqlStr, vars...).ExecContext(ctx)
 	if err != nil {
 		return
 	}
@@ -101,9 +223,10 @@ func (d *DB[T]) DeleteContext(ctx context.Context) (rowsAffected int64, err erro
 		}
 	}
 
-	d.Clause.Set(clause.Delete, table.TableName)
+	d.snapshots.drop(d.tableName())
+	d.Clause.Set(clause.Delete, d.quotedTableName())
 	sqlStr, vars := d.Clause.Build(clause.Delete, clause.Where)
-	result, err := d.Raw(sqlStr, vars...).ExecContext(ctx)
+	result, err := d.raw(sqlStr, vars...).ExecContext(ctx)
 	if err != nil {
 		return
 	}
@@ -130,24 +253,27 @@ func (d *DB[T]) SelectContext(ctx context.Context) (results []T, err error) {
 		}
 	}
 
-	d.Clause.Set(clause.Select, table.TableName, table.FieldNames)
-	sqlStr, vars := d.Clause.Build(clause.Select, clause.Where, clause.OrderBy, clause.Limit)
-	rows, err := d.Raw(sqlStr, vars...).QueryRowsContext(ctx)
+	fields, err := d.fields()
+	if err != nil {
+		return
+	}
+	track := d.track && len(fields) == len(table.Fields)
+	d.Clause.Set(clause.Select, d.quotedTableName(), d.quoteColumns(d.qualify(fieldNames(fields))))
+	sqlStr, vars := d.Clause.Build(clause.Select, clause.Join, clause.Where, clause.GroupBy, clause.Having, clause.OrderBy, clause.Limit)
+	rows, err := d.raw(sqlStr, vars...).QueryRowsContext(ctx)
 
 	if err != nil {
 		return
 	}
-	defer func() {
-		err = rows.Close()
-	}()
+	defer rows.Close()
 
 	// results set
 	for rows.Next() {
 		dest := reflect.New(d.DestType.Type()).Elem()
 
-		fieldValues := make([]interface{}, len(table.StructFieldNames))
-		for i, name := range table.StructFieldNames {
-			fieldValues[i] = dest.FieldByName(name).Addr().Interface()
+		fieldValues := make([]interface{}, len(fields))
+		for i, field := range fields {
+			fieldValues[i] = scanDest(field, dest)
 		}
 
 		if err = rows.Scan(fieldValues...); err != nil {
@@ -156,6 +282,12 @@ func (d *DB[T]) SelectContext(ctx context.Context) (results []T, err error) {
 
 		t := dest.Interface().(T)
 		results = append(results, t)
+		if track {
+			d.snapshot(dest)
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return
 	}
 
 	if afterQuery, ok := table.Model.(AfterQuery[T]); ok {
@@ -197,9 +329,9 @@ func (d *DB[T]) CountContext(ctx context.Context) (n int64, err error) {
 		}
 	}
 
-	d.Clause.Set(clause.Count, d.RefTable().TableName)
-	sqlStr, vars := d.Clause.Build(clause.Count, clause.Where)
-	row := d.Raw(sqlStr, vars...).QueryRowContext(ctx)
+	d.Clause.Set(clause.Count, d.quotedTableName())
+	sqlStr, vars := d.Clause.Build(clause.Count, clause.Join, clause.Where)
+	row := d.raw(sqlStr, vars...).QueryRowContext(ctx)
 	if err = row.Scan(&n); err != nil {
 		return
 	}
@@ -217,7 +349,13 @@ func (d *DB[T]) Update(record map[string]interface{}) (int64, error) {
 	return d.UpdateContext(context.Background(), record)
 }
 
-func (d *DB[T]) UpdateContext(ctx context.Context, record map[string]interface{}) (rowsAffected int64, err error) {
+func (d *DB[T]) UpdateContext(ctx context.Context, record map[string]interface{}) (int64, error) {
+	d.snapshots.drop(d.tableName())
+	return d.update(ctx, record)
+}
+
+// update runs the update of record, keeping the snapshots of the table for Save.
+func (d *DB[T]) update(ctx context.Context, record map[string]interface{}) (rowsAffected int64, err error) {
 	table := d.RefTable()
 	if beforeUpdate, ok := table.Model.(BeforeUpdate[T]); ok {
 		if err = beforeUpdate.BeforeUpdate(ctx, d); err != nil {
@@ -225,10 +363,19 @@ func (d *DB[T]) UpdateContext(ctx context.Context, record map[string]interface{}
 		}
 	}
 
-	d.Clause.Set(clause.Update, table.TableName, record)
+	if record, err = d.updateRecord(record); err != nil || len(record) == 0 {
+		d.Clear()
+		return
+	}
+
+	quoted := make(map[string]interface{}, len(record))
+	for column, value := range record {
+		quoted[d.dialect.Quote(column)] = value
+	}
+	d.Clause.Set(clause.Update, d.quotedTableName(), quoted)
 	sqlStr, vars := d.Clause.Build(clause.Update, clause.Where)
 
-	result, err := d.Raw(sqlStr, vars...).ExecContext(ctx)
+	result, err := d.raw(sqlStr, vars...).ExecContext(ctx)
 	if err != nil {
 		return
 	}
@@ -243,14 +390,262 @@ func (d *DB[T]) UpdateContext(ctx context.Context, record map[string]interface{}
 	return result.RowsAffected()
 }
 
+// FindByID returns the record with the primary key ids, given in the order of
+// schema.Table.PrimaryFields for composite keys.
+func (d *DB[T]) FindByID(ids ...any) (T, error) {
+	return d.FindByIDContext(context.Background(), ids...)
+}
+
+func (d *DB[T]) FindByIDContext(ctx context.Context, ids ...any) (result T, err error) {
+	if err = d.wherePrimary(ids); err != nil {
+		return
+	}
+	return d.FirstContext(ctx)
+}
+
+// DeleteByID deletes the record with the primary key ids.
+func (d *DB[T]) DeleteByID(ids ...any) (int64, error) {
+	return d.DeleteByIDContext(context.Background(), ids...)
+}
+
+func (d *DB[T]) DeleteByIDContext(ctx context.Context, ids ...any) (rowsAffected int64, err error) {
+	if err = d.wherePrimary(ids); err != nil {
+		return
+	}
+	return d.DeleteContext(ctx)
+}
+
+// UpdateByID updates the columns of record in the record with the primary key ids.
+func (d *DB[T]) UpdateByID(record map[string]interface{}, ids ...any) (int64, error) {
+	return d.UpdateByIDContext(context.Background(), record, ids...)
+}
+
+func (d *DB[T]) UpdateByIDContext(ctx context.Context, record map[string]interface{}, ids ...any) (rowsAffected int64, err error) {
+	if err = d.wherePrimary(ids); err != nil {
+		return
+	}
+	return d.UpdateContext(ctx, record)
+}
+
+// Save creates value when its primary key is zero and generated by the database,
+// otherwise it updates the other columns of the record with the primary key of value.
+// Only the columns changed since the record was read by a Select following Track are
+// updated, all of them when it was not or its table was written since other than by
+// Save. The records read or saved in a transaction are only remembered by the session
+// once it commits.
+func (d *DB[T]) Save(value *T) (int64, error) {
+	return d.SaveContext(context.Background(), value)
+}
+
+func (d *DB[T]) SaveContext(ctx context.Context, value *T) (rowsAffected int64, err error) {
+	table := d.RefTable()
+	if len(table.PrimaryFields) == 0 {
+		return 0, ErrMissingPrimaryKey
+	}
+
+	ids := table.PrimaryValues(value)
+	if isZeroKey(ids) {
+		if len(table.PrimaryFields) != 1 || table.PrimaryFields[0] != table.AutoIncrementField {
+			return 0, ErrZeroPrimaryKey
+		}
+		return d.CreateContext(ctx, value)
+	}
+
+	key := d.snapshotKey(ids)
+	snapshot, loaded := d.snapshots.get(key)
+	record := make(map[string]interface{})
+	values := table.RecordValues(value)
+	for i, field := range table.Fields {
+		if table.IsPrimaryField(field) || (loaded && reflect.DeepEqual(snapshot[i], values[i])) {
+			continue
+		}
+		record[field.Name] = values[i]
+	}
+	if len(record) == 0 {
+		return 0, nil
+	}
+
+	if err = d.wherePrimary(ids); err != nil {
+		return
+	}
+	if rowsAffected, err = d.update(ctx, record); err != nil {
+		return
+	}
+	if loaded {
+		d.snapshots.set(key, copyValues(values))
+	}
+	return
+}
+
+// wherePrimary restricts the statement to the record with the primary key ids.
+func (d *DB[T]) wherePrimary(ids []any) error {
+	primaryFields := d.RefTable().PrimaryFields
+	if len(primaryFields) == 0 {
+		return ErrMissingPrimaryKey
+	}
+	if len(ids) != len(primaryFields) {
+		return fmt.Errorf("%d primary key values for %d primary key columns", len(ids), len(primaryFields))
+	}
+
+	conditions := make([]string, len(primaryFields))
+	for i, field := range primaryFields {
+		conditions[i] = d.dialect.Quote(field.Name) + " = ?"
+	}
+	d.Where(strings.Join(conditions, " AND "), ids...)
+	return nil
+}
+
+// isZeroKey reports whether any of the primary key values ids is unset.
+func isZeroKey(ids []any) bool {
+	for _, id := range ids {
+		if id == nil || reflect.ValueOf(id).IsZero() {
+			return true
+		}
+	}
+	return false
+}
+
+// Updates updates the columns of the non-zero fields of value but the primary key,
+// or the columns chosen with Columns whatever their values.
+func (d *DB[T]) Updates(value T) (int64, error) {
+	return d.UpdatesContext(context.Background(), value)
+}
+
+func (d *DB[T]) UpdatesContext(ctx context.Context, value T) (int64, error) {
+	fields, err := d.fields()
+	if err != nil {
+		return 0, err
+	}
+
+	table := d.RefTable()
+	record := make(map[string]interface{})
+	v := reflect.ValueOf(value)
+	for _, field := range fields {
+		fieldValue := field.ReflectValueOf(v)
+		if len(d.selects) == 0 && (table.IsPrimaryField(field) || !fieldValue.IsValid() || fieldValue.IsZero()) {
+			continue
+		}
+		if fieldValue.IsValid() {
+			record[field.Name] = fieldValue.Interface()
+		} else {
+			record[field.Name] = nil
+		}
+	}
+	return d.UpdateContext(ctx, record)
+}
+
+// Track has the following Select remember the column values of the records it reads,
+// Save then updates the columns changed since.
+func (d *DB[T]) Track() *DB[T] {
+	d.track = true
+	return d
+}
+
+// Table runs the following statement against the table name instead of the table of
+// the model, such as the monthly partition events_202610.
+func (d *DB[T]) Table(name string) *DB[T] {
+	d.table = name
+	return d
+}
+
+// holdTableName keeps the table chosen with Table across the statements of operations
+// running several of them such as AutoMigrate, the returned func releases it.
+func (d *DB[T]) holdTableName() (release func()) {
+	if d.holdTable {
+		return func() {}
+	}
+	d.holdTable = true
+	return func() {
+		d.holdTable = false
+		d.table = ""
+	}
+}
+
 func (d *DB[T]) Limit(num int) *DB[T] {
 	d.Clause.Set(clause.Limit, num)
 	return d
 }
 
-func (d *DB[T]) Where(desc string, args ...interface{}) *DB[T] {
-	var vars []interface{}
-	d.Clause.Set(clause.Where, append(append(vars, desc), args...)...)
+// Where adds the condition query to the previous ones with AND, query is either a
+// clause.Expression or a SQL condition with ? placeholders for args.
+func (d *DB[T]) Where(query interface{}, args ...interface{}) *DB[T] {
+	d.where = clause.And(d.where, condition(query, args))
+	d.Clause.Set(clause.Where, d.where)
+	return d
+}
+
+// Or adds the condition query to the previous ones with OR.
+func (d *DB[T]) Or(query interface{}, args ...interface{}) *DB[T] {
+	d.where = clause.Or(d.where, condition(query, args))
+	d.Clause.Set(clause.Where, d.where)
+	return d
+}
+
+func condition(query interface{}, args []interface{}) clause.Expression {
+	if expression, ok := query.(clause.Expression); ok {
+		return expression
+	}
+	return clause.Expr(fmt.Sprint(query), args...)
+}
+
+// Columns restricts the following Insert, Update or Select to the columns, and makes
+// Updates write them even when they are zero.
+func (d *DB[T]) Columns(columns ...string) *DB[T] {
+	d.selects = append(d.selects, columns...)
+	return d
+}
+
+// Omit leaves the columns out of the following Insert, Update or Select.
+func (d *DB[T]) Omit(columns ...string) *DB[T] {
+	d.omits = append(d.omits, columns...)
+	return d
+}
+
+// Joins adds the join clause query with its args to the following Select or Count,
+// such as "LEFT JOIN company ON company.id = user.company_id".
+func (d *DB[T]) Joins(query string, args ...interface{}) *DB[T] {
+	return d.join(clause.Expr(query, args...))
+}
+
+// InnerJoin joins table on the condition on, a clause.Expression or a SQL condition
+// with ? placeholders for args.
+func (d *DB[T]) InnerJoin(table string, on interface{}, args ...interface{}) *DB[T] {
+	return d.join(clause.JoinTable{Kind: "INNER", Table: table, On: condition(on, args)})
+}
+
+// LeftJoin left joins table on the condition on, as InnerJoin.
+func (d *DB[T]) LeftJoin(table string, on interface{}, args ...interface{}) *DB[T] {
+	return d.join(clause.JoinTable{Kind: "LEFT", Table: table, On: condition(on, args)})
+}
+
+func (d *DB[T]) join(join clause.Expression) *DB[T] {
+	d.joins = append(d.joins, join)
+	d.Clause.Set(clause.Join, d.joins...)
+	return d
+}
+
+// GroupBy groups the rows of the following Select or Scan by columns.
+func (d *DB[T]) GroupBy(columns ...string) *DB[T] {
+	d.groups = columns
+	vars := make([]interface{}, len(columns))
+	for i, column := range columns {
+		vars[i] = column
+	}
+	d.Clause.Set(clause.GroupBy, vars...)
+	return d
+}
+
+// Having adds the condition query on the groups to the previous ones with AND, as Where.
+func (d *DB[T]) Having(query interface{}, args ...interface{}) *DB[T] {
+	d.having = clause.And(d.having, condition(query, args))
+	d.Clause.Set(clause.Having, d.having)
+	return d
+}
+
+// Project sets the select list of the following Scan, such as "company_id" and
+// "count(*) AS employees".
+func (d *DB[T]) Project(columns ...string) *DB[T] {
+	d.projects = append(d.projects, columns...)
 	return d
 }
 
@@ -258,3 +653,116 @@ func (d *DB[T]) OrderBy(desc string) *DB[T] {
 	d.Clause.Set(clause.OrderBy, desc)
 	return d
 }
+
+// fields returns the fields of the columns chosen with Columns and Omit in the order of
+// the table, it clears the statement when they name unknown columns.
+func (d *DB[T]) fields() ([]*schema.Field, error) {
+	table := d.RefTable()
+	if err := d.checkColumns(append(append([]string(nil), d.selects...), d.omits...)); err != nil {
+		d.Clear()
+		return nil, err
+	}
+	if len(d.selects) == 0 && len(d.omits) == 0 {
+		return table.Fields, nil
+	}
+
+	var fields []*schema.Field
+	for _, field := range table.Fields {
+		if d.chosen(field.Name) {
+			fields = append(fields, field)
+		}
+	}
+	return fields, nil
+}
+
+// updateRecord validates the columns of record, leaving out those not chosen with
+// Columns and Omit.
+func (d *DB[T]) updateRecord(record map[string]interface{}) (map[string]interface{}, error) {
+	columns := make([]string, 0, len(record))
+	for column := range record {
+		columns = append(columns, column)
+	}
+	if err := d.checkColumns(append(append(columns, d.selects...), d.omits...)); err != nil {
+		return nil, err
+	}
+	if len(d.selects) == 0 && len(d.omits) == 0 {
+		return record, nil
+	}
+
+	chosen := make(map[string]interface{}, len(record))
+	for column, value := range record {
+		if d.chosen(column) {
+			chosen[column] = value
+		}
+	}
+	return chosen, nil
+}
+
+// chosen reports whether column is selected by Columns and not omitted by Omit.
+func (d *DB[T]) chosen(column string) bool {
+	return (len(d.selects) == 0 || contains(d.selects, column)) && !contains(d.omits, column)
+}
+
+// checkColumns returns ErrInvalidColumn for the first column that is not in the table.
+func (d *DB[T]) checkColumns(columns []string) error {
+	table := d.RefTable()
+	for _, column := range columns {
+		if table.GetField(column) == nil {
+			return fmt.Errorf("%w: %s", ErrInvalidColumn, column)
+		}
+	}
+	return nil
+}
+
+func fieldNames(fields []*schema.Field) []string {
+	names := make([]string, len(fields))
+	for i, field := range fields {
+		names[i] = field.Name
+	}
+	return names
+}
+
+// fieldValues returns the values of fields in the record value.
+func fieldValues(fields []*schema.Field, value any) []interface{} {
+	v := reflect.Indirect(reflect.ValueOf(value))
+	values := make([]interface{}, len(fields))
+	for i, field := range fields {
+		if fieldValue := field.ReflectValueOf(v); fieldValue.IsValid() {
+			values[i] = fieldValue.Interface()
+		}
+	}
+	return values
+}
+
+// scanDest returns the destination of the column of field in the struct value v, which
+// discards the column when field is behind a nil embedded pointer that cannot be set.
+func scanDest(field *schema.Field, v reflect.Value) interface{} {
+	if fieldValue := field.ReflectValueOf(v); fieldValue.IsValid() {
+		return fieldValue.Addr().Interface()
+	}
+	return new(interface{})
+}
+
+// quoteColumns quotes the column names of a statement built by d.
+func (d *DB[T]) quoteColumns(columns []string) []string {
+	quoted := make([]string, len(columns))
+	for i, column := range columns {
+		quoted[i] = d.dialect.Quote(column)
+	}
+	return quoted
+}
+
+// qualify qualifies the columns of a select list with the table name when tables are
+// joined, unless they already name their table as the columns of result types embedding
+// models with prefixes such as "company.".
+func (d *DB[T]) qualify(columns []string) []string {
+	if len(d.joins) == 0 {
+		return columns
+	}
+	for i, column := range columns {
+		if !strings.Contains(column, ".") {
+			columns[i] = d.tableName() + "." + column
+		}
+	}
+	return columns
+}
@@ -1,29 +1,225 @@
 package session
 
 import (
+	"database/sql"
+	"database/sql/driver"
 	"testing"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-venus/venus/clause"
 	"github.com/go-venus/venus/dialect"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestNew(t *testing.T) {
 	type User struct {
 		Name string `venus:"name"`
 	}
-	db, mock, err := sqlmock.New()
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
 	if err != nil {
 		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
 	}
 	defer db.Close()
 
-	// mock.ExpectBegin()
-	// mock.ExpectExec("UPDATE products").WillReturnResult(sqlmock.NewResult(1, 1))
-	mock.ExpectExec("INSERT INTO `user` (name) VALUES (?)").WithArgs("1").WillReturnResult(sqlmock.NewResult(1, 1))
-	// mock.ExpectCommit()
+	mock.ExpectExec("INSERT INTO `user` (`name`) VALUES (?)").WithArgs("1").WillReturnResult(sqlmock.NewResult(1, 1))
 
 	var TestDial, _ = dialect.GetDialect("mysql")
 
 	s := New[User](db, TestDial)
-	s.Insert(User{Name: "1"})
+	n, err := s.Insert(User{Name: "1"})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, n)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresPlaceholder(t *testing.T) {
+	type User struct {
+		Name string `venus:"name"`
+	}
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery(`SELECT "name" FROM "user" WHERE name = $1 AND name <> '?' LIMIT $2`).
+		WithArgs("Tom", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("Tom"))
+
+	pg, _ := dialect.GetDialect("postgres")
+	s := New[User](db, pg)
+	users, err := s.Where("name = ? AND name <> '?'", "Tom").Limit(1).Select()
+	assert.NoError(t, err)
+	assert.Equal(t, []User{{Name: "Tom"}}, users)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReplaceNotSupported(t *testing.T) {
+	type User struct {
+		Name string `venus:"name"`
+	}
+	db, _, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	pg, _ := dialect.GetDialect("postgres")
+	_, err = New[User](db, pg).Replace(User{Name: "Tom"})
+	assert.ErrorIs(t, err, dialect.ErrNotSupported)
+}
+
+func TestFindByID(t *testing.T) {
+	type Enrollment struct {
+		StudentId int64 `venus:"PRIMARY KEY"`
+		CourseId  int64 `venus:"PRIMARY KEY"`
+		Grade     string
+	}
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT `student_id`,`course_id`,`grade` FROM `enrollment` WHERE `student_id` = ? AND `course_id` = ? LIMIT ?").
+		WithArgs(1, 2, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"student_id", "course_id", "grade"}).AddRow(1, 2, "A"))
+
+	mysql, _ := dialect.GetDialect("mysql")
+	s := New[Enrollment](db, mysql)
+	enrollment, err := s.FindByID(1, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, Enrollment{StudentId: 1, CourseId: 2, Grade: "A"}, enrollment)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	_, err = s.FindByID(1)
+	assert.EqualError(t, err, "1 primary key values for 2 primary key columns")
+
+	_, err = New[struct{ Name string }](db, mysql).DeleteByID(1)
+	assert.ErrorIs(t, err, ErrMissingPrimaryKey)
+}
+
+func TestSaveChanged(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT `id`,`code`,`price` FROM `product` WHERE `id` = ? LIMIT ?").
+		WithArgs(1, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "code", "price"}).AddRow(1, "a", 1.5))
+	mock.ExpectExec("UPDATE `product` SET `price` = ? WHERE `id` = ?").
+		WithArgs(2.5, int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	mysql, _ := dialect.GetDialect("mysql")
+	s := New[Product](db, mysql)
+	product, err := s.Track().FindByID(1)
+	assert.NoError(t, err)
+
+	// unchanged records are not written
+	n, err := s.Save(&product)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 0, n)
+
+	product.Price = 2.5
+	n, err = s.Save(&product)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, n)
+
+	n, err = s.Save(&product)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 0, n)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWhereAccumulates(t *testing.T) {
+	type User struct {
+		Name string `venus:"name"`
+		Age  int    `venus:"age"`
+	}
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery(`SELECT count(*) FROM "user" WHERE (name = $1 AND (age > $2)) OR (age BETWEEN $3 AND $4)`).
+		WithArgs("Tom", 18, 60, 70).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+	mock.ExpectQuery(`SELECT count(*) FROM "user"`).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(5))
+
+	pg, _ := dialect.GetDialect("postgres")
+	s := New[User](db, pg)
+	n, err := s.Where(clause.Eq("name", "Tom")).Where("age > ?", 18).Or("age BETWEEN ? AND ?", 60, 70).Count()
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, n)
+
+	// conditions do not outlive the statement
+	n, err = s.Count()
+	assert.NoError(t, err)
+	assert.EqualValues(t, 5, n)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestNamedParameters(t *testing.T) {
+	type User struct {
+		Name string `venus:"name"`
+		Age  int    `venus:"age"`
+	}
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery(`SELECT "name","age" FROM "user" WHERE age BETWEEN $1 AND $2 AND name IN ($3, $4)`).
+		WithArgs(18, 30, "Tom", "Sam").
+		WillReturnRows(sqlmock.NewRows([]string{"name", "age"}).AddRow("Tom", 20))
+	mock.ExpectExec("UPDATE user SET age = age + 1 WHERE name = $1").
+		WithArgs("Tom").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	pg, _ := dialect.GetDialect("postgres")
+	s := New[User](db, pg)
+	users, err := s.Where("age BETWEEN @min AND @max AND name IN @names",
+		map[string]any{"min": 18, "max": 30, "names": []string{"Tom", "Sam"}}).Select()
+	assert.NoError(t, err)
+	assert.Equal(t, []User{{Name: "Tom", Age: 20}}, users)
+
+	_, err = s.Raw("UPDATE user SET age = age + 1 WHERE name = :name", sql.Named("name", "Tom")).Exec()
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// passthrough hands every argument to the mock as it is.
+type passthrough struct{}
+
+func (passthrough) ConvertValue(v interface{}) (driver.Value, error) {
+	return v, nil
+}
+
+func TestSliceColumnValues(t *testing.T) {
+	type User struct {
+		Id   int64
+		Tags []string `venus:"type:text[]"`
+	}
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual), sqlmock.ValueConverterOption(passthrough{}))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	// slices are expanded in the conditions only, and only once
+	mock.ExpectExec(`UPDATE "user" SET "tags" = $1 WHERE id IN ($2, $3)`).
+		WithArgs([]string{"a", "b"}, 1, 2).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	pg, _ := dialect.GetDialect("postgres")
+	n, err := New[User](db, pg).Where("id IN ?", []int{1, 2}).Update(map[string]interface{}{"tags": []string{"a", "b"}})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, n)
+	assert.NoError(t, mock.ExpectationsWereMet())
 }
@@ -0,0 +1,80 @@
+package session
+
+import (
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-venus/venus/clause"
+	"github.com/go-venus/venus/dialect"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type Company struct {
+	Id   int64
+	Name string
+}
+
+type Employee struct {
+	Id        int64
+	Name      string
+	CompanyId int64
+}
+
+// EmployeeCompany is an employee read with their company.
+type EmployeeCompany struct {
+	Employee Employee `venus:"embedded;embeddedPrefix:employee."`
+	Company  Company  `venus:"embedded;embeddedPrefix:company."`
+}
+
+func (EmployeeCompany) TableName() string {
+	return "employee"
+}
+
+func TestJoins(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery(`SELECT "employee"."id","employee"."name","employee"."company_id" FROM "employee" `+
+		"INNER JOIN company ON company.id = employee.company_id AND company.name = $1 WHERE employee.name LIKE $2").
+		WithArgs("venus", "T%").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "company_id"}).AddRow(1, "Tom", 2))
+	mock.ExpectQuery(`SELECT count(*) FROM "employee" LEFT JOIN company ON company.id = employee.company_id WHERE company.id IS NULL`).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+
+	pg, _ := dialect.GetDialect("postgres")
+	s := New[Employee](db, pg)
+	employees, err := s.InnerJoin("company", "company.id = employee.company_id AND company.name = ?", "venus").
+		Where(clause.Like("employee.name", "T%")).Select()
+	require.NoError(t, err)
+	assert.Equal(t, []Employee{{Id: 1, Name: "Tom", CompanyId: 2}}, employees)
+
+	n, err := s.Joins("LEFT JOIN company ON company.id = employee.company_id").Where(clause.IsNull("company.id")).Count()
+	require.NoError(t, err)
+	assert.EqualValues(t, 0, n)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSQLiteJoins(t *testing.T) {
+	companies := newSQLiteSession[Company](t)
+	_, err := companies.Insert(Company{Id: 1, Name: "venus"}, Company{Id: 2, Name: "mars"})
+	require.NoError(t, err)
+
+	employees := New[Employee](companies.db, companies.dialect)
+	require.NoError(t, employees.CreateTable())
+	_, err = employees.Insert(Employee{Id: 1, Name: "Tom", CompanyId: 1}, Employee{Id: 2, Name: "Sam", CompanyId: 2}, Employee{Id: 3, Name: "Bob"})
+	require.NoError(t, err)
+
+	s := New[EmployeeCompany](companies.db, companies.dialect)
+	results, err := s.InnerJoin("company", "company.id = employee.company_id").OrderBy("employee.id").Select()
+	require.NoError(t, err)
+	assert.Equal(t, []EmployeeCompany{
+		{Employee: Employee{Id: 1, Name: "Tom", CompanyId: 1}, Company: Company{Id: 1, Name: "venus"}},
+		{Employee: Employee{Id: 2, Name: "Sam", CompanyId: 2}, Company: Company{Id: 2, Name: "mars"}},
+	}, results)
+	var scanned []EmployeeCompany
+	err = Scan(s.InnerJoin("company", "company.id = employee.company_id").OrderBy("employee.id"), &scanned)
+	require.NoError(t, err)
+	assert.Equal(t, results, scanned)
+}
@@ -0,0 +1,132 @@
+package session
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/go-venus/venus/schema"
+)
+
+type columnInfo struct {
+	name     string
+	dataType string
+	size     sql.NullInt64
+}
+
+// AutoMigrate creates the table, or adds the missing columns and indexes of an
+// existing one and widens columns whose size grew. Nothing is ever dropped.
+func (d *DB[T]) AutoMigrate() error {
+	return d.AutoMigrateContext(context.Background())
+}
+
+func (d *DB[T]) AutoMigrateContext(ctx context.Context) error {
+	defer d.holdTableName()()
+
+	if !d.HasTableContext(ctx) {
+		return d.CreateTableContext(ctx)
+	}
+
+	columns, err := d.columns(ctx)
+	if err != nil {
+		return err
+	}
+	indexes, err := d.indexNames(ctx)
+	if err != nil {
+		return err
+	}
+
+	table := d.RefTable()
+	tableName := d.quotedTableName()
+	var stmts []string
+	for _, field := range table.Fields {
+		column, ok := columns[field.Name]
+		if !ok {
+			// existing rows need a value for the new column
+			if field.NotNull && !field.HasDefault {
+				return fmt.Errorf("cannot add NOT NULL column %s without a default", field.Name)
+			}
+			definition, err := d.columnDefinition(field, false, false)
+			if err != nil {
+				return err
+			}
+			stmts = append(stmts, fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s", tableName, definition))
+			stmts = append(stmts, d.commentStmts(field)...)
+			if field.Unique {
+				unique := &schema.Index{Unique: true, Fields: []*schema.Field{field}}
+				if !indexes[unique.NameOn(d.tableName())] {
+					stmts = append(stmts, d.createIndexSQL(unique))
+				}
+			}
+			continue
+		}
+
+		if needsWidening(field, column) {
+			definition, err := d.columnDefinition(field, false, false)
+			if err != nil {
+				return err
+			}
+			if stmt := d.dialect.ModifyColumnSQL(d.tableName(), field, definition); stmt != "" {
+				stmts = append(stmts, stmt)
+			}
+		}
+	}
+	for _, index := range table.Indexes {
+		if !indexes[index.NameOn(d.tableName())] {
+			stmts = append(stmts, d.createIndexSQL(index))
+		}
+	}
+
+	return d.execStmts(ctx, stmts)
+}
+
+// needsWidening reports whether the sized column of field is shorter than declared.
+func needsWidening(field *schema.Field, column columnInfo) bool {
+	if field.DataType != schema.String && field.DataType != schema.Bytes {
+		return false
+	}
+	return field.Size > 0 && column.size.Valid && column.size.Int64 > 0 && column.size.Int64 < int64(field.Size)
+}
+
+// columns returns the columns of the live table by name.
+func (d *DB[T]) columns(ctx context.Context) (columns map[string]columnInfo, err error) {
+	query, values := d.dialect.ColumnsSQL(d.tableName())
+	rows, err := d.raw(query, values...).QueryRowsContext(ctx)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	columns = map[string]columnInfo{}
+	for rows.Next() {
+		var column columnInfo
+		if err = rows.Scan(&column.name, &column.dataType, &column.size); err != nil {
+			return
+		}
+		columns[column.name] = column
+	}
+	return columns, rows.Err()
+}
+
+// indexNames returns the names of the indexes of the live table.
+func (d *DB[T]) indexNames(ctx context.Context) (indexes map[string]bool, err error) {
+	query, values := d.dialect.IndexesSQL(d.tableName())
+	rows, err := d.raw(query, values...).QueryRowsContext(ctx)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	indexes = map[string]bool{}
+	for rows.Next() {
+		var (
+			name, column string
+			unique       bool
+		)
+		if err = rows.Scan(&name, &unique, &column); err != nil {
+			return
+		}
+		indexes[name] = true
+	}
+	return indexes, rows.Err()
+}
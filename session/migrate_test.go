@@ -0,0 +1,116 @@
+package session
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-venus/venus/dialect"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type Member struct {
+	Id    int64  `venus:"column:id;PRIMARY KEY"`
+	Name  string `venus:"column:name;size:64;index"`
+	Email string `venus:"column:email;uniqueIndex:uidx_member_email"`
+}
+
+func TestAutoMigrate(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer db.Close()
+
+	mysql, _ := dialect.GetDialect("mysql")
+	existSQL, _ := mysql.TableExistSQL("member")
+	columnsSQL, _ := mysql.ColumnsSQL("member")
+	indexesSQL, _ := mysql.IndexesSQL("member")
+
+	mock.ExpectQuery(existSQL).WithArgs("member").
+		WillReturnRows(sqlmock.NewRows([]string{"table_name"}).AddRow("member"))
+	mock.ExpectQuery(columnsSQL).WithArgs("member").
+		WillReturnRows(sqlmock.NewRows([]string{"column_name", "data_type", "character_maximum_length"}).
+			AddRow("id", "bigint", nil).
+			AddRow("name", "varchar", 32))
+	mock.ExpectQuery(indexesSQL).WithArgs("member").
+		WillReturnRows(sqlmock.NewRows([]string{"index_name", "unique", "column_name"}).
+			AddRow("PRIMARY", true, "id"))
+	mock.ExpectExec("ALTER TABLE `member` MODIFY COLUMN `name` varchar(64)").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("ALTER TABLE `member` ADD COLUMN `email` varchar(255)").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("CREATE INDEX `idx_member_name` ON `member` (`name`)").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("CREATE UNIQUE INDEX `uidx_member_email` ON `member` (`email`)").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	assert.NoError(t, New[Member](db, mysql).AutoMigrate())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSQLiteAutoMigrate(t *testing.T) {
+	s := newSQLiteSession[Member](t)
+	require.NoError(t, s.DropTable())
+	_, err := s.Raw(`CREATE TABLE member (id integer PRIMARY KEY, name text)`).Exec()
+	require.NoError(t, err)
+	_, err = s.Raw(`INSERT INTO member (id, name) VALUES (?, ?)`, 1, "Tom").Exec()
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		require.NoError(t, s.AutoMigrate())
+	}
+
+	columns, err := s.columns(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, columns, 3)
+	assert.Contains(t, columns, "email")
+
+	indexes, err := s.indexNames(context.Background())
+	require.NoError(t, err)
+	assert.True(t, indexes["idx_member_name"])
+	assert.True(t, indexes["uidx_member_email"])
+
+	_, err = s.Raw(`UPDATE member SET email = name`).Exec()
+	require.NoError(t, err)
+	members, err := s.Select()
+	require.NoError(t, err)
+	assert.Equal(t, []Member{{Id: 1, Name: "Tom", Email: "Tom"}}, members)
+}
+
+func TestSQLiteAutoMigrateCreatesTable(t *testing.T) {
+	s := newSQLiteSession[Member](t)
+	require.NoError(t, s.DropTable())
+	require.NoError(t, s.AutoMigrate())
+	assert.True(t, s.HasTable())
+	require.NoError(t, s.CreateTable(IfNotExists()), "existing indexes are kept")
+
+	indexes, err := s.indexNames(context.Background())
+	require.NoError(t, err)
+	assert.True(t, indexes["idx_member_name"])
+}
+
+func TestSQLiteAutoMigrateAddColumn(t *testing.T) {
+	type Coupon struct {
+		Id    int64  `venus:"column:id;PRIMARY KEY;AUTOINCREMENT"`
+		Code  string `venus:"column:code;UNIQUE"`
+		Count int    `venus:"column:count;NOT NULL;default:0"`
+	}
+	s := newSQLiteSession[Coupon](t)
+	require.NoError(t, s.DropTable())
+	_, err := s.Raw(`CREATE TABLE coupon (id integer PRIMARY KEY AUTOINCREMENT)`).Exec()
+	require.NoError(t, err)
+	_, err = s.Raw(`INSERT INTO coupon (id) VALUES (1), (2)`).Exec()
+	require.NoError(t, err)
+
+	require.NoError(t, s.AutoMigrate())
+	_, err = s.Raw(`UPDATE coupon SET code = ?`, "SALE").Exec()
+	assert.Error(t, err, "code is unique")
+
+	type Voucher struct {
+		Id   int64  `venus:"column:id;PRIMARY KEY"`
+		Code string `venus:"column:code;NOT NULL"`
+	}
+	_, err = s.Raw(`CREATE TABLE voucher (id integer PRIMARY KEY)`).Exec()
+	require.NoError(t, err)
+	assert.EqualError(t, New[Voucher](s.db, s.dialect).AutoMigrate(), "cannot add NOT NULL column code without a default")
+}
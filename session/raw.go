@@ -7,7 +7,16 @@ import (
 	"github.com/go-venus/venus/clause"
 )
 
+// Raw appends sql with its values to the statement. Named parameters are bound as by
+// clause.Named and slice values expanded into a placeholder per element as by clause.Expand.
 func (d *DB[T]) Raw(sql string, values ...any) *DB[T] {
+	sql, values = clause.Expand(clause.Named(sql, values))
+	return d.raw(sql, values...)
+}
+
+// raw appends sql with its values as they are, the statements built from clauses have
+// their parameters bound already and slice values are single column values.
+func (d *DB[T]) raw(sql string, values ...any) *DB[T] {
 	d.Sql.WriteString(sql)
 	d.Sql.WriteString(" ")
 	d.SqlVars = append(d.SqlVars, values...)
@@ -30,7 +39,7 @@ func (d *DB[T]) QueryRowContext(ctx context.Context) *sql.Row {
 		}
 	}()
 
-	return d.getDB().QueryRowContext(ctx, d.Sql.String(), d.SqlVars...)
+	return d.getDB().QueryRowContext(ctx, d.sqlString(), d.SqlVars...)
 }
 
 func (d *DB[T]) QueryRows() (rows *sql.Rows, err error) {
@@ -49,7 +58,7 @@ func (d *DB[T]) QueryRowsContext(ctx context.Context) (rows *sql.Rows, err error
 		}
 	}()
 
-	rows, err = d.getDB().QueryContext(ctx, d.Sql.String(), d.SqlVars...)
+	rows, err = d.getDB().QueryContext(ctx, d.sqlString(), d.SqlVars...)
 	return
 }
 
@@ -69,16 +78,31 @@ func (d *DB[T]) ExecContext(ctx context.Context) (result sql.Result, err error)
 		}
 	}()
 
-	result, err = d.getDB().ExecContext(ctx, d.Sql.String(), d.SqlVars...)
+	result, err = d.getDB().ExecContext(ctx, d.sqlString(), d.SqlVars...)
 	return
 }
 
 func (d *DB[T]) Clear() {
 	d.Sql.Reset()
 	d.SqlVars = nil
+	d.selects, d.omits = nil, nil
+	d.where, d.joins = nil, nil
+	d.groups, d.having, d.projects = nil, nil, nil
+	d.track = false
+	if !d.holdTable {
+		d.table = ""
+	}
 	d.Clause = clause.Clause{}
 }
 
+// sqlString returns the built SQL with placeholders rendered by the dialect.
+func (d *DB[T]) sqlString() string {
+	if d.dialect == nil {
+		return d.Sql.String()
+	}
+	return clause.Rebind(d.Sql.String(), d.dialect.BindVar)
+}
+
 func (d *DB[T]) getDB() db {
 	if d.tx != nil {
 		return d.tx
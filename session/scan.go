@@ -0,0 +1,155 @@
+package session
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/go-venus/venus/clause"
+	"github.com/go-venus/venus/schema"
+)
+
+// Scan runs the select of d into dest, a slice of another struct than the model such as
+// a report row. The select list is the one set with Project, the result columns are
+// matched to the fields of R by name and columns without a field are discarded.
+// Without Project the columns of R are selected and read back in order, so that fields
+// such as employee.id and company.id of a join are told apart.
+func Scan[T, R any](d *DB[T], dest *[]R) error {
+	return ScanContext(context.Background(), d, dest)
+}
+
+func ScanContext[T, R any](ctx context.Context, d *DB[T], dest *[]R) (err error) {
+	var model R
+	table, err := d.registry.Parse(&model)
+	if err != nil {
+		d.Clear()
+		return
+	}
+
+	if beforeQuery, ok := d.RefTable().Model.(BeforeQuery[T]); ok {
+		if err = beforeQuery.BeforeQuery(ctx, d); err != nil {
+			return
+		}
+	}
+
+	columns := d.projects
+	var fields []*schema.Field // 未调用Project时按位置对应R的字段
+	if len(columns) == 0 {
+		columns = d.quoteColumns(d.qualify(append([]string(nil), table.FieldNames...)))
+		fields = table.Fields
+	}
+	d.Clause.Set(clause.Select, d.quotedTableName(), columns)
+	sqlStr, vars := d.Clause.Build(clause.Select, clause.Join, clause.Where, clause.GroupBy, clause.Having, clause.OrderBy, clause.Limit)
+	rows, err := d.raw(sqlStr, vars...).QueryRowsContext(ctx)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	names, err := rows.Columns()
+	if err != nil {
+		return
+	}
+	for rows.Next() {
+		var record R
+		v := reflect.ValueOf(&record).Elem()
+		values := make([]interface{}, len(names))
+		for i, name := range names {
+			if fields != nil {
+				values[i] = scanDest(fields[i], v)
+			} else if field := table.GetField(name); field != nil {
+				values[i] = scanDest(field, v)
+			} else {
+				values[i] = new(interface{})
+			}
+		}
+
+		if err = rows.Scan(values...); err != nil {
+			return
+		}
+		*dest = append(*dest, record)
+	}
+	if err = rows.Err(); err != nil {
+		return
+	}
+
+	if afterQuery, ok := d.RefTable().Model.(AfterQuery[T]); ok {
+		err = afterQuery.AfterQuery(ctx, d)
+	}
+	return
+}
+
+// Sum returns the sum of column over the rows matched by d, the zero value of V when
+// there is none.
+func Sum[V, T any](d *DB[T], column string) (V, error) {
+	return SumContext[V](context.Background(), d, column)
+}
+
+func SumContext[V, T any](ctx context.Context, d *DB[T], column string) (V, error) {
+	return aggregate[V](ctx, d, "SUM("+column+")")
+}
+
+// Avg returns the average of column over the rows matched by d.
+func Avg[V, T any](d *DB[T], column string) (V, error) {
+	return AvgContext[V](context.Background(), d, column)
+}
+
+func AvgContext[V, T any](ctx context.Context, d *DB[T], column string) (V, error) {
+	return aggregate[V](ctx, d, "AVG("+column+")")
+}
+
+// Min returns the smallest value of column over the rows matched by d.
+func Min[V, T any](d *DB[T], column string) (V, error) {
+	return MinContext[V](context.Background(), d, column)
+}
+
+func MinContext[V, T any](ctx context.Context, d *DB[T], column string) (V, error) {
+	return aggregate[V](ctx, d, "MIN("+column+")")
+}
+
+// Max returns the largest value of column over the rows matched by d.
+func Max[V, T any](d *DB[T], column string) (V, error) {
+	return MaxContext[V](context.Background(), d, column)
+}
+
+func MaxContext[V, T any](ctx context.Context, d *DB[T], column string) (V, error) {
+	return aggregate[V](ctx, d, "MAX("+column+")")
+}
+
+// CountDistinct returns the number of distinct values of column over the rows matched by d.
+func CountDistinct[T any](d *DB[T], column string) (int64, error) {
+	return CountDistinctContext(context.Background(), d, column)
+}
+
+func CountDistinctContext[T any](ctx context.Context, d *DB[T], column string) (int64, error) {
+	return aggregate[int64](ctx, d, "COUNT(DISTINCT "+column+")")
+}
+
+// aggregate returns the value of the aggregate expression over the rows matched by d,
+// NULL scans into the zero value of V. Grouped rows have a value per group, which Scan
+// reads, so it returns ErrGrouped after GroupBy or Having.
+func aggregate[V, T any](ctx context.Context, d *DB[T], expression string) (result V, err error) {
+	if len(d.groups) > 0 || d.having != nil {
+		d.Clear()
+		return result, ErrGrouped
+	}
+	if beforeQuery, ok := d.RefTable().Model.(BeforeQuery[T]); ok {
+		if err = beforeQuery.BeforeQuery(ctx, d); err != nil {
+			return
+		}
+	}
+
+	d.Clause.Set(clause.Select, d.quotedTableName(), []string{expression})
+	sqlStr, vars := d.Clause.Build(clause.Select, clause.Join, clause.Where)
+	var value *V
+	if err = d.raw(sqlStr, vars...).QueryRowContext(ctx).Scan(&value); err != nil {
+		return
+	}
+	if value != nil {
+		result = *value
+	}
+
+	if afterQuery, ok := d.RefTable().Model.(AfterQuery[T]); ok {
+		err = afterQuery.AfterQuery(ctx, d)
+	}
+	return
+}
@@ -0,0 +1,88 @@
+package session
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-venus/venus/clause"
+	"github.com/go-venus/venus/dialect"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// AgeGroup is a row of accounts grouped by age.
+type AgeGroup struct {
+	Age      int
+	Accounts int64
+	Names    string
+}
+
+func TestScanGroupBy(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT age,count(*) AS accounts FROM `account` WHERE active = ? GROUP BY age HAVING count(*) > ? ORDER BY age").
+		WithArgs(true, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"age", "accounts"}).AddRow(18, 2).AddRow(20, 3))
+
+	mysql, _ := dialect.GetDialect("mysql")
+	s := New[Account](db, mysql)
+	var groups []AgeGroup
+	err = Scan(s.Project("age", "count(*) AS accounts").Where("active = ?", true).
+		GroupBy("age").Having("count(*) > ?", 1).OrderBy("age"), &groups)
+	require.NoError(t, err)
+	assert.Equal(t, []AgeGroup{{Age: 18, Accounts: 2}, {Age: 20, Accounts: 3}}, groups)
+
+	// the value of every group is read by Scan
+	_, err = Max[int](s.GroupBy("name").Having("count(*) > ?", 1), "age")
+	assert.ErrorIs(t, err, ErrGrouped)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSQLiteAggregates(t *testing.T) {
+	s := newSQLiteSession[Account](t)
+
+	now := time.Now().UTC().Truncate(time.Second)
+	_, err := s.Insert(
+		Account{Id: 1, Name: "Tom", Age: 18, Active: true, CreatedAt: now},
+		Account{Id: 2, Name: "Sam", Age: 18, CreatedAt: now},
+		Account{Id: 3, Name: "Bob", Age: 30, Active: true, CreatedAt: now},
+	)
+	require.NoError(t, err)
+
+	sum, err := Sum[int64](s.DB, "age")
+	require.NoError(t, err)
+	assert.EqualValues(t, 66, sum)
+
+	avg, err := Avg[float64](s.Where("active = ?", true), "age")
+	require.NoError(t, err)
+	assert.Equal(t, 24.0, avg)
+
+	lowest, err := Min[string](s.DB, "name")
+	require.NoError(t, err)
+	assert.Equal(t, "Bob", lowest)
+
+	highest, err := Max[int](s.Where(clause.Gt("age", 100)), "age")
+	require.NoError(t, err)
+	assert.Equal(t, 0, highest)
+
+	distinct, err := CountDistinct(s.DB, "age")
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, distinct)
+
+	var groups []AgeGroup
+	err = Scan(s.Project("age", "count(*) AS accounts", "group_concat(name) AS names").
+		GroupBy("age").Having(clause.Gt("count(*)", 1)), &groups)
+	require.NoError(t, err)
+	require.Len(t, groups, 1)
+	assert.Equal(t, 18, groups[0].Age)
+	assert.EqualValues(t, 2, groups[0].Accounts)
+
+	// without Project the columns of the result type are selected
+	type named struct{ Name string }
+	var names []named
+	require.NoError(t, Scan(s.Where("age = ?", 30), &names))
+	assert.Equal(t, []named{{Name: "Bob"}}, names)
+}
@@ -0,0 +1,140 @@
+package session
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/go-venus/venus/schema"
+)
+
+// maxSnapshots caps the records remembered by a session, the oldest are forgotten first.
+const maxSnapshots = 1024
+
+// recordKey identifies a record by its table and primary key.
+type recordKey struct {
+	table string
+	id    string
+}
+
+// snapshots holds the column values of the records read by Select after Track keyed by
+// table and primary key, Save compares against them to update the changed columns only.
+// The snapshots taken in a transaction are kept apart from the session's and only merged
+// into them when it commits.
+type snapshots struct {
+	mu      sync.Mutex
+	values  map[recordKey][]interface{}
+	keys    []recordKey     // 按写入顺序, 超出上限时淘汰最早的
+	dropped map[string]bool // 事务中写过的表, 提交时清除会话中这些表的快照
+	parent  *snapshots
+}
+
+// newSnapshots returns the snapshots of a session, or of a transaction of the session
+// holding parent.
+func newSnapshots(parent *snapshots) *snapshots {
+	return &snapshots{values: make(map[recordKey][]interface{}), dropped: make(map[string]bool), parent: parent}
+}
+
+func (s *snapshots) get(key recordKey) ([]interface{}, bool) {
+	s.mu.Lock()
+	values, ok := s.values[key]
+	dropped := s.dropped[key.table]
+	s.mu.Unlock()
+	if ok || dropped || s.parent == nil {
+		return values, ok
+	}
+	return s.parent.get(key)
+}
+
+func (s *snapshots) set(key recordKey, values []interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.values[key]; !ok {
+		s.keys = append(s.keys, key)
+		if len(s.keys) > maxSnapshots {
+			delete(s.values, s.keys[0])
+			s.keys = s.keys[1:]
+		}
+	}
+	s.values[key] = values
+}
+
+// drop forgets the snapshots of the records of table, which was written without Save.
+func (s *snapshots) drop(table string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	keys := s.keys[:0]
+	for _, key := range s.keys {
+		if key.table == table {
+			delete(s.values, key)
+		} else {
+			keys = append(keys, key)
+		}
+	}
+	s.keys = keys
+	if s.parent != nil {
+		s.dropped[table] = true
+	}
+}
+
+// commit merges the snapshots of a committed transaction into the session's.
+func (s *snapshots) commit() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for table := range s.dropped {
+		s.parent.drop(table)
+	}
+	for _, key := range s.keys {
+		s.parent.set(key, s.values[key])
+	}
+}
+
+// snapshot records the column values of dest, a record of T read from the database.
+func (d *DB[T]) snapshot(dest reflect.Value) {
+	table := d.RefTable()
+	if len(table.PrimaryFields) == 0 {
+		return
+	}
+	d.snapshots.set(d.snapshotKey(table.PrimaryValues(dest.Interface())), copyValues(table.RecordValues(dest.Interface())))
+}
+
+// snapshotKey identifies the record with the primary key ids in the table of d. Numeric
+// ids are converted to the type of their primary key field, and every id is quoted with
+// its type so that the keys of different ids never collide.
+func (d *DB[T]) snapshotKey(ids []interface{}) recordKey {
+	primaryFields := d.RefTable().PrimaryFields
+	var id strings.Builder
+	for i, value := range ids {
+		if i < len(primaryFields) {
+			value = convertNumber(value, primaryFields[i])
+		}
+		fmt.Fprintf(&id, " %T:%q", value, fmt.Sprint(value))
+	}
+	return recordKey{table: d.tableName(), id: id.String()}
+}
+
+// convertNumber converts the number value to the type of field when it is a number too.
+func convertNumber(value interface{}, field *schema.Field) interface{} {
+	v := reflect.ValueOf(value)
+	if !v.IsValid() || !isNumber(v.Kind()) || !isNumber(field.FieldType.Kind()) {
+		return value
+	}
+	return v.Convert(field.FieldType).Interface()
+}
+
+func isNumber(kind reflect.Kind) bool {
+	return kind >= reflect.Int && kind <= reflect.Float64
+}
+
+// copyValues copies the byte slices of values, which share their array with the record.
+func copyValues(values []interface{}) []interface{} {
+	copied := make([]interface{}, len(values))
+	for i, value := range values {
+		if b, ok := value.([]byte); ok && b != nil {
+			value = append([]byte(nil), b...)
+		}
+		copied[i] = value
+	}
+	return copied
+}
@@ -0,0 +1,113 @@
+package session
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-venus/venus/dialect"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnapshots(t *testing.T) {
+	s := newSnapshots(nil)
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j <= maxSnapshots; j++ {
+				key := recordKey{table: "a", id: fmt.Sprint(i, j)}
+				s.set(key, []interface{}{j})
+				s.get(key)
+			}
+		}(i)
+	}
+	wg.Wait()
+	assert.Len(t, s.values, maxSnapshots)
+	b, c := recordKey{table: "b", id: "1"}, recordKey{table: "c", id: "1"}
+	s.set(b, []interface{}{2})
+
+	tx := newSnapshots(s)
+	tx.set(c, []interface{}{1})
+	tx.drop("b")
+	_, ok := tx.get(b)
+	assert.False(t, ok)
+	_, ok = s.get(b)
+	assert.True(t, ok, "the transaction did not commit")
+
+	tx.commit()
+	_, ok = s.get(b)
+	assert.False(t, ok)
+	_, ok = s.get(c)
+	assert.True(t, ok)
+}
+
+func TestSnapshotKey(t *testing.T) {
+	s := New[Account](nil, nil)
+	assert.NotEqual(t, s.snapshotKey([]interface{}{"a b"}), s.snapshotKey([]interface{}{"a", "b"}))
+	assert.NotEqual(t, s.snapshotKey([]interface{}{1}), s.snapshotKey([]interface{}{"1"}))
+	assert.Equal(t, s.snapshotKey([]interface{}{int64(1)}), s.snapshotKey([]interface{}{1}))
+}
+
+func TestSQLiteSaveRollback(t *testing.T) {
+	s := newSQLiteSession[Account](t)
+
+	now := time.Now().UTC().Truncate(time.Second)
+	_, err := s.Insert(Account{Id: 1, Name: "Tom", CreatedAt: now})
+	require.NoError(t, err)
+	account, err := s.Track().FindByID(1)
+	require.NoError(t, err)
+
+	account.Name = "Sam"
+	errRollback := errors.New("rollback")
+	err = s.Transaction(func(tx *Tx[Account]) error {
+		n, err := tx.Save(&account)
+		require.NoError(t, err)
+		assert.EqualValues(t, 1, n)
+		return errRollback
+	})
+	assert.ErrorIs(t, err, errRollback)
+
+	// the rolled back update is written again
+	n, err := s.Save(&account)
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, n)
+	account, err = s.FindByID(1)
+	require.NoError(t, err)
+	assert.Equal(t, "Sam", account.Name)
+}
+
+func TestSQLiteSaveAfterUpdate(t *testing.T) {
+	s := newSQLiteSession[Account](t)
+
+	_, err := s.Insert(Account{Id: 1, Name: "a"})
+	require.NoError(t, err)
+	account, err := s.Track().FindByID(1)
+	require.NoError(t, err)
+	_, err = s.UpdateByID(map[string]interface{}{"name": "b"}, 1)
+	require.NoError(t, err)
+
+	// the update forgot the snapshot, so every column is written
+	account.Name = "a"
+	n, err := s.Save(&account)
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, n)
+	account, err = s.FindByID(1)
+	require.NoError(t, err)
+	assert.Equal(t, "a", account.Name)
+}
+
+func TestSaveZeroKey(t *testing.T) {
+	type Note struct {
+		Id   int64
+		Text string
+	}
+
+	mysql, _ := dialect.GetDialect("mysql")
+	_, err := New[Note](nil, mysql).Save(&Note{Text: "a"})
+	assert.ErrorIs(t, err, ErrZeroPrimaryKey)
+}
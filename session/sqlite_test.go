@@ -0,0 +1,414 @@
+package session
+
+import (
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-venus/venus/dialect"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type Account struct {
+	Id        int64     `venus:"column:id;PRIMARY KEY"`
+	Name      string    `venus:"column:name"`
+	Age       int       `venus:"column:age"`
+	Active    bool      `venus:"column:active"`
+	CreatedAt time.Time `venus:"column:created_at"`
+}
+
+// newSQLiteSession opens a sqlite database in a temporary directory with T's table created.
+func newSQLiteSession[T any](t *testing.T) *Session[T] {
+	t.Helper()
+
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "venus.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	dial, err := dialect.GetDialect("sqlite3")
+	require.NoError(t, err)
+
+	s := New[T](db, dial)
+	require.NoError(t, s.DropTable())
+	require.NoError(t, s.CreateTable())
+	return s
+}
+
+// testDialects runs test against a mysql session on sqlmock, expecting the statements
+// set up by expect, and again against a real sqlite database.
+func testDialects[T any](t *testing.T, expect func(mock sqlmock.Sqlmock), test func(t *testing.T, s *Session[T])) {
+	t.Run("mysql", func(t *testing.T) {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		require.NoError(t, err)
+		defer db.Close()
+
+		expect(mock)
+		mysql, _ := dialect.GetDialect("mysql")
+		test(t, New[T](db, mysql))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("sqlite3", func(t *testing.T) {
+		test(t, newSQLiteSession[T](t))
+	})
+}
+
+func TestCRUD(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+	columns := []string{"id", "name", "age", "active", "created_at"}
+
+	testDialects(t, func(mock sqlmock.Sqlmock) {
+		mock.ExpectExec("INSERT INTO `account` (`id`,`name`,`age`,`active`,`created_at`) VALUES (?, ?, ?, ?, ?), (?, ?, ?, ?, ?)").
+			WithArgs(1, "Tom", 18, true, now, 2, "Sam", 25, false, now).
+			WillReturnResult(sqlmock.NewResult(2, 2))
+		mock.ExpectQuery("SELECT `id`,`name`,`age`,`active`,`created_at` FROM `account` ORDER BY id").
+			WillReturnRows(sqlmock.NewRows(columns).AddRow(1, "Tom", 18, true, now).AddRow(2, "Sam", 25, false, now))
+		mock.ExpectQuery("SELECT `id`,`name`,`age`,`active`,`created_at` FROM `account` WHERE age > ? LIMIT ?").
+			WithArgs(20, 1).
+			WillReturnRows(sqlmock.NewRows(columns).AddRow(2, "Sam", 25, false, now))
+		mock.ExpectExec("UPDATE `account` SET `age` = ? WHERE name = ?").
+			WithArgs(30, "Tom").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery("SELECT count(*) FROM `account` WHERE age >= ?").
+			WithArgs(25).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+		mock.ExpectExec("DELETE FROM `account` WHERE name = ?").
+			WithArgs("Sam").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery("SELECT `id`,`name`,`age`,`active`,`created_at` FROM `account` WHERE name = ? LIMIT ?").
+			WithArgs("Sam", 1).
+			WillReturnRows(sqlmock.NewRows(columns))
+	}, func(t *testing.T, s *Session[Account]) {
+		n, err := s.Insert(
+			Account{Id: 1, Name: "Tom", Age: 18, Active: true, CreatedAt: now},
+			Account{Id: 2, Name: "Sam", Age: 25, CreatedAt: now},
+		)
+		require.NoError(t, err)
+		assert.EqualValues(t, 2, n)
+
+		accounts, err := s.OrderBy("id").Select()
+		require.NoError(t, err)
+		assert.Equal(t, []Account{
+			{Id: 1, Name: "Tom", Age: 18, Active: true, CreatedAt: now},
+			{Id: 2, Name: "Sam", Age: 25, CreatedAt: now},
+		}, accounts)
+
+		first, err := s.Where("age > ?", 20).First()
+		require.NoError(t, err)
+		assert.Equal(t, "Sam", first.Name)
+
+		n, err = s.Where("name = ?", "Tom").Update(map[string]interface{}{"age": 30})
+		require.NoError(t, err)
+		assert.EqualValues(t, 1, n)
+
+		count, err := s.Where("age >= ?", 25).Count()
+		require.NoError(t, err)
+		assert.EqualValues(t, 2, count)
+
+		n, err = s.Where("name = ?", "Sam").Delete()
+		require.NoError(t, err)
+		assert.EqualValues(t, 1, n)
+
+		_, err = s.Where("name = ?", "Sam").First()
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+}
+
+func TestSQLiteHasTable(t *testing.T) {
+	s := newSQLiteSession[Account](t)
+	assert.True(t, s.HasTable())
+
+	require.NoError(t, s.DropTable())
+	assert.False(t, s.HasTable())
+}
+
+func TestReplace(t *testing.T) {
+	type Setting struct {
+		Key   string `venus:"column:key;PRIMARY KEY"`
+		Value string `venus:"column:value"`
+	}
+
+	testDialects(t, func(mock sqlmock.Sqlmock) {
+		mock.ExpectExec("INSERT INTO `setting` (`key`,`value`) VALUES (?, ?)").
+			WithArgs("theme", "light").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("INSERT INTO `setting` (`key`,`value`) VALUES (?, ?)").
+			WithArgs("theme", "dark").
+			WillReturnError(errors.New("Duplicate entry 'theme' for key 'PRIMARY'"))
+		mock.ExpectExec("REPLACE INTO `setting` (`key`,`value`) VALUES (?, ?)").
+			WithArgs("theme", "dark").
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectQuery("SELECT `key`,`value` FROM `setting`").
+			WillReturnRows(sqlmock.NewRows([]string{"key", "value"}).AddRow("theme", "dark"))
+	}, func(t *testing.T, s *Session[Setting]) {
+		_, err := s.Insert(Setting{Key: "theme", Value: "light"})
+		require.NoError(t, err)
+		_, err = s.Insert(Setting{Key: "theme", Value: "dark"})
+		assert.Error(t, err)
+
+		_, err = s.Replace(Setting{Key: "theme", Value: "dark"})
+		require.NoError(t, err)
+
+		settings, err := s.Select()
+		require.NoError(t, err)
+		assert.Equal(t, []Setting{{Key: "theme", Value: "dark"}}, settings)
+	})
+}
+
+func TestTransaction(t *testing.T) {
+	insert := "INSERT INTO `account` (`id`,`name`,`age`,`active`,`created_at`) VALUES (?, ?, ?, ?, ?)"
+	errRollback := errors.New("rollback")
+
+	testDialects(t, func(mock sqlmock.Sqlmock) {
+		mock.ExpectBegin()
+		mock.ExpectExec(insert).
+			WithArgs(1, "Tom", 0, false, time.Time{}).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectRollback()
+		mock.ExpectQuery("SELECT count(*) FROM `account`").
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+		mock.ExpectBegin()
+		mock.ExpectExec(insert).
+			WithArgs(1, "Tom", 0, false, time.Time{}).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+		mock.ExpectQuery("SELECT count(*) FROM `account`").
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	}, func(t *testing.T, s *Session[Account]) {
+		err := s.Transaction(func(tx *Tx[Account]) error {
+			if _, err := tx.Insert(Account{Id: 1, Name: "Tom"}); err != nil {
+				return err
+			}
+			return errRollback
+		})
+		assert.ErrorIs(t, err, errRollback)
+
+		count, err := s.Count()
+		require.NoError(t, err)
+		assert.EqualValues(t, 0, count)
+
+		err = s.Transaction(func(tx *Tx[Account]) error {
+			_, err := tx.Insert(Account{Id: 1, Name: "Tom"})
+			return err
+		})
+		require.NoError(t, err)
+
+		count, err = s.Count()
+		require.NoError(t, err)
+		assert.EqualValues(t, 1, count)
+	})
+}
+
+type Base struct {
+	Id        int64
+	CreatedAt time.Time
+}
+
+type Location struct {
+	City   string
+	Street string
+}
+
+type Shop struct {
+	Base
+	Name    string
+	Address Location `venus:"embedded;embeddedPrefix:address_"`
+}
+
+func TestEmbedded(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+	shop := Shop{Base: Base{Id: 1, CreatedAt: now}, Name: "venus", Address: Location{City: "Rome", Street: "Via Appia"}}
+
+	testDialects(t, func(mock sqlmock.Sqlmock) {
+		mock.ExpectExec("INSERT INTO `shop` (`id`,`created_at`,`name`,`address_city`,`address_street`) VALUES (?, ?, ?, ?, ?)").
+			WithArgs(1, now, "venus", "Rome", "Via Appia").
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectQuery("SELECT `id`,`created_at`,`name`,`address_city`,`address_street` FROM `shop` WHERE address_city = ? LIMIT ?").
+			WithArgs("Rome", 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "name", "address_city", "address_street"}).
+				AddRow(1, now, "venus", "Rome", "Via Appia"))
+	}, func(t *testing.T, s *Session[Shop]) {
+		_, err := s.Insert(shop)
+		require.NoError(t, err)
+
+		found, err := s.Where("address_city = ?", "Rome").First()
+		require.NoError(t, err)
+		assert.Equal(t, shop, found)
+	})
+}
+
+type audit struct {
+	CreatedBy string
+}
+
+// Note embeds an unexported pointer that the session cannot allocate.
+type Note struct {
+	Id int64
+	*audit
+	Text string
+}
+
+func TestSQLiteEmbeddedPointer(t *testing.T) {
+	s := newSQLiteSession[Note](t)
+
+	_, err := s.Insert(Note{Id: 1, audit: &audit{CreatedBy: "Tom"}, Text: "a"}, Note{Id: 2, Text: "b"})
+	require.NoError(t, err)
+
+	notes, err := s.OrderBy("id").Select()
+	require.NoError(t, err)
+	assert.Equal(t, []Note{{Id: 1, Text: "a"}, {Id: 2, Text: "b"}}, notes)
+
+	var texts []struct {
+		*audit
+		Text string
+	}
+	require.NoError(t, Scan(s.OrderBy("id"), &texts))
+	require.Len(t, texts, 2)
+	assert.Equal(t, "a", texts[0].Text)
+	assert.Nil(t, texts[0].audit)
+}
+
+func TestByID(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+	columns := []string{"id", "name", "age", "active", "created_at"}
+
+	testDialects(t, func(mock sqlmock.Sqlmock) {
+		mock.ExpectExec("INSERT INTO `account` (`id`,`name`,`age`,`active`,`created_at`) VALUES (?, ?, ?, ?, ?)").
+			WithArgs(1, "Tom", 18, false, now).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec("UPDATE `account` SET `age` = ? WHERE `id` = ?").
+			WithArgs(20, 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery("SELECT `id`,`name`,`age`,`active`,`created_at` FROM `account` WHERE `id` = ? LIMIT ?").
+			WithArgs(1, 1).
+			WillReturnRows(sqlmock.NewRows(columns).AddRow(1, "Tom", 20, false, now))
+		mock.ExpectExec("UPDATE `account` SET `name` = ? WHERE `id` = ?").
+			WithArgs("Sam", int64(1)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("INSERT INTO `account` (`name`,`age`,`active`,`created_at`) VALUES (?, ?, ?, ?)").
+			WithArgs("Bob", 0, false, now).
+			WillReturnResult(sqlmock.NewResult(2, 1))
+		mock.ExpectQuery("SELECT count(*) FROM `account`").
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+		mock.ExpectQuery("SELECT `id`,`name`,`age`,`active`,`created_at` FROM `account` WHERE `id` = ? LIMIT ?").
+			WithArgs(1, 1).
+			WillReturnRows(sqlmock.NewRows(columns).AddRow(1, "Sam", 20, false, now))
+		mock.ExpectExec("DELETE FROM `account` WHERE `id` = ?").
+			WithArgs(1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery("SELECT `id`,`name`,`age`,`active`,`created_at` FROM `account` WHERE `id` = ? LIMIT ?").
+			WithArgs(1, 1).
+			WillReturnRows(sqlmock.NewRows(columns))
+	}, func(t *testing.T, s *Session[Account]) {
+		_, err := s.Insert(Account{Id: 1, Name: "Tom", Age: 18, CreatedAt: now})
+		require.NoError(t, err)
+
+		n, err := s.UpdateByID(map[string]interface{}{"age": 20}, 1)
+		require.NoError(t, err)
+		assert.EqualValues(t, 1, n)
+
+		account, err := s.Track().FindByID(1)
+		require.NoError(t, err)
+		assert.Equal(t, 20, account.Age)
+
+		account.Name = "Sam"
+		n, err = s.Save(&account)
+		require.NoError(t, err)
+		assert.EqualValues(t, 1, n)
+
+		_, err = s.Save(&Account{Name: "Bob", CreatedAt: now})
+		require.NoError(t, err)
+		count, err := s.Count()
+		require.NoError(t, err)
+		assert.EqualValues(t, 2, count)
+
+		account, err = s.FindByID(1)
+		require.NoError(t, err)
+		assert.Equal(t, "Sam", account.Name)
+
+		n, err = s.DeleteByID(1)
+		require.NoError(t, err)
+		assert.EqualValues(t, 1, n)
+
+		_, err = s.FindByID(1)
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+}
+
+func TestExpand(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+
+	testDialects(t, func(mock sqlmock.Sqlmock) {
+		mock.ExpectExec("INSERT INTO `account` (`id`,`name`,`age`,`active`,`created_at`) VALUES (?, ?, ?, ?, ?), (?, ?, ?, ?, ?), (?, ?, ?, ?, ?)").
+			WithArgs(1, "Tom", 18, false, now, 2, "Sam", 25, false, now, 3, "Bob", 30, false, now).
+			WillReturnResult(sqlmock.NewResult(3, 3))
+		mock.ExpectQuery("SELECT count(*) FROM `account` WHERE id IN (?, ?)").
+			WithArgs(1, 3).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+		mock.ExpectQuery("SELECT count(*) FROM `account` WHERE 1 = 0").
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+		mock.ExpectQuery("SELECT count(*) FROM `account` WHERE 1 = 1").
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+		mock.ExpectQuery("SELECT count(*) FROM `account` WHERE (id, name) IN ((?, ?), (?, ?))").
+			WithArgs(1, "Tom", 2, "Bob").
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+		mock.ExpectQuery("SELECT name FROM account WHERE age IN (?, ?) ORDER BY age DESC").
+			WithArgs(18, 25).
+			WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("Sam"))
+	}, func(t *testing.T, s *Session[Account]) {
+		_, err := s.Insert(
+			Account{Id: 1, Name: "Tom", Age: 18, CreatedAt: now},
+			Account{Id: 2, Name: "Sam", Age: 25, CreatedAt: now},
+			Account{Id: 3, Name: "Bob", Age: 30, CreatedAt: now},
+		)
+		require.NoError(t, err)
+
+		count, err := s.Where("id IN ?", []int64{1, 3}).Count()
+		require.NoError(t, err)
+		assert.EqualValues(t, 2, count)
+
+		count, err = s.Where("id IN ?", []int64{}).Count()
+		require.NoError(t, err)
+		assert.EqualValues(t, 0, count)
+
+		count, err = s.Where("id NOT IN ?", []int64{}).Count()
+		require.NoError(t, err)
+		assert.EqualValues(t, 3, count)
+
+		count, err = s.Where("(id, name) IN ?", [][]any{{1, "Tom"}, {2, "Bob"}}).Count()
+		require.NoError(t, err)
+		assert.EqualValues(t, 1, count)
+
+		var name string
+		require.NoError(t, s.Raw("SELECT name FROM account WHERE age IN (?) ORDER BY age DESC", []int{18, 25}).QueryRow().Scan(&name))
+		assert.Equal(t, "Sam", name)
+	})
+}
+
+// Order names its table and a column with reserved words.
+type Order struct {
+	Id    int64 `venus:"PRIMARY KEY"`
+	Group string
+}
+
+func TestSQLiteReservedNames(t *testing.T) {
+	s := newSQLiteSession[Order](t)
+	_, err := s.Insert(Order{Id: 1, Group: "a"}, Order{Id: 2, Group: "b"})
+	require.NoError(t, err)
+
+	_, err = s.UpdateByID(map[string]interface{}{"group": "c"}, 1)
+	require.NoError(t, err)
+	order, err := s.FindByID(1)
+	require.NoError(t, err)
+	assert.Equal(t, Order{Id: 1, Group: "c"}, order)
+
+	_, err = s.DeleteByID(2)
+	require.NoError(t, err)
+	count, err := s.Count()
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, count)
+}
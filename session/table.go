@@ -1,37 +1,203 @@
 package session
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
 	"github.com/go-venus/venus/schema"
 )
 
+// TableOption configures the CREATE TABLE statement.
+type TableOption func(*tableOptions)
+
+type tableOptions struct {
+	ifNotExists bool
+	options     string
+}
+
+// IfNotExists creates the table only when it does not exist yet.
+func IfNotExists() TableOption {
+	return func(o *tableOptions) {
+		o.ifNotExists = true
+	}
+}
+
+// WithTableOptions appends options after the column definitions, such as
+// "ENGINE=InnoDB DEFAULT CHARSET=utf8mb4" for MySQL.
+func WithTableOptions(options string) TableOption {
+	return func(o *tableOptions) {
+		o.options = options
+	}
+}
+
 func (d *DB[T]) RefTable() *schema.Table {
 	return d.refTable
 }
 
-func (d *DB[T]) CreateTable() error {
+func (d *DB[T]) CreateTable(opts ...TableOption) error {
+	return d.CreateTableContext(context.Background(), opts...)
+}
+
+func (d *DB[T]) CreateTableContext(ctx context.Context, opts ...TableOption) error {
+	defer d.holdTableName()()
+
+	var options tableOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
 	table := d.RefTable()
-	var columns []string
+	var primaryKeys []string
 	for _, field := range table.Fields {
-		columns = append(columns, fmt.Sprintf("%d %d", field.StructName, field.DataType))
+		if field.PrimaryKey {
+			primaryKeys = append(primaryKeys, d.dialect.Quote(field.Name))
+		}
 	}
-	desc := strings.Join(columns, ",")
-	_, err := d.Raw(fmt.Sprintf("CREATE TABLE %d (%d);", table.TableName, desc)).Exec()
-	return err
+
+	var columns, stmts []string
+	for _, field := range table.Fields {
+		column, err := d.columnDefinition(field, len(primaryKeys) == 1, true)
+		if err != nil {
+			return err
+		}
+		columns = append(columns, column)
+		stmts = append(stmts, d.commentStmts(field)...)
+	}
+	if len(primaryKeys) > 1 {
+		columns = append(columns, fmt.Sprintf("PRIMARY KEY (%s)", strings.Join(primaryKeys, ", ")))
+	}
+	var sql strings.Builder
+	sql.WriteString("CREATE TABLE ")
+	if options.ifNotExists {
+		sql.WriteString("IF NOT EXISTS ")
+	}
+	sql.WriteString(d.quotedTableName())
+	sql.WriteString(" (")
+	sql.WriteString(strings.Join(columns, ", "))
+	sql.WriteString(")")
+	if options.options != "" {
+		sql.WriteString(" ")
+		sql.WriteString(options.options)
+	}
+
+	if _, err := d.raw(sql.String()).ExecContext(ctx); err != nil {
+		return err
+	}
+
+	// the table may already exist with its indexes
+	existing := map[string]bool{}
+	if options.ifNotExists && len(table.Indexes) > 0 {
+		var err error
+		if existing, err = d.indexNames(ctx); err != nil {
+			return err
+		}
+	}
+	for _, index := range table.Indexes {
+		if !existing[index.NameOn(d.tableName())] {
+			stmts = append(stmts, d.createIndexSQL(index))
+		}
+	}
+	return d.execStmts(ctx, stmts)
+}
+
+// columnDefinition returns the definition of the column of field, primaryKey and unique
+// tell whether those constraints of the field are declared inline.
+func (d *DB[T]) columnDefinition(field *schema.Field, primaryKey, unique bool) (string, error) {
+	dataType := d.dialect.DataTypeOf(field)
+	if dataType == "" {
+		return "", fmt.Errorf("unsupported data type %s of field %s", field.FieldType, field.StructName)
+	}
+
+	column := []string{d.dialect.Quote(field.Name), dataType}
+	if field.PrimaryKey && primaryKey {
+		column = append(column, "PRIMARY KEY")
+		// auto increment is only valid on a single primary key declared inline
+		if field.AutoIncrement {
+			if autoIncrement := d.dialect.AutoIncrement(); autoIncrement != "" {
+				column = append(column, autoIncrement)
+			}
+		}
+	}
+	if field.NotNull {
+		column = append(column, "NOT NULL")
+	}
+	if field.Unique && unique {
+		column = append(column, "UNIQUE")
+	}
+	if field.HasDefault {
+		column = append(column, "DEFAULT", field.DefaultValue)
+	}
+	if field.Comment != "" {
+		if inline, _ := d.dialect.ColumnComment(d.tableName(), field.Name, field.Comment); inline != "" {
+			column = append(column, inline)
+		}
+	}
+	return strings.Join(column, " "), nil
+}
+
+// commentStmts returns the statements commenting the column of field for dialects
+// that do not support inline comments.
+func (d *DB[T]) commentStmts(field *schema.Field) []string {
+	if field.Comment == "" {
+		return nil
+	}
+	if _, stmt := d.dialect.ColumnComment(d.tableName(), field.Name, field.Comment); stmt != "" {
+		return []string{stmt}
+	}
+	return nil
+}
+
+func (d *DB[T]) createIndexSQL(index *schema.Index) string {
+	columns := make([]string, 0, len(index.Fields))
+	for _, field := range index.Fields {
+		columns = append(columns, d.dialect.Quote(field.Name))
+	}
+
+	var unique string
+	if index.Unique {
+		unique = "UNIQUE "
+	}
+	return fmt.Sprintf("CREATE %sINDEX %s ON %s (%s)",
+		unique, d.dialect.Quote(index.NameOn(d.tableName())), d.quotedTableName(), strings.Join(columns, ", "))
+}
+
+func (d *DB[T]) execStmts(ctx context.Context, stmts []string) error {
+	for _, stmt := range stmts {
+		if _, err := d.raw(stmt).ExecContext(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func (d *DB[T]) DropTable() error {
-	_, err := d.Raw(fmt.Sprintf("DROP TABLE IF EXISTS %d", d.RefTable().TableName)).Exec()
+	_, err := d.raw(fmt.Sprintf("DROP TABLE IF EXISTS %s", d.quotedTableName())).Exec()
 	return err
 }
 
 // HasTable returns true of the table exists
 func (d *DB[T]) HasTable() bool {
-	sql, values := d.dialect.TableExistSQL(d.RefTable().TableName)
-	row := d.Raw(sql, values...).QueryRow()
+	return d.HasTableContext(context.Background())
+}
+
+func (d *DB[T]) HasTableContext(ctx context.Context) bool {
+	tableName := d.tableName()
+	sql, values := d.dialect.TableExistSQL(tableName)
+	row := d.raw(sql, values...).QueryRowContext(ctx)
 	var tmp string
 	_ = row.Scan(&tmp)
-	return tmp == d.RefTable().TableName
+	return tmp == tableName
+}
+
+// quotedTableName returns the name of the table quoted by the dialect.
+func (d *DB[T]) quotedTableName() string {
+	return d.dialect.Quote(d.tableName())
+}
+
+// tableName returns the name of the table the statements run against.
+func (d *DB[T]) tableName() string {
+	if d.table != "" {
+		return d.table
+	}
+	return d.RefTable().TableName
 }
@@ -0,0 +1,189 @@
+package session
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-venus/venus/dialect"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type Product struct {
+	Id    int64   `venus:"column:id;PRIMARY KEY;AUTO_INCREMENT"`
+	Code  string  `venus:"column:code;size:32;NOT NULL;UNIQUE;comment:stock keeping unit"`
+	Price float64 `venus:"column:price;precision:10;scale:2;default:0"`
+}
+
+type OrderItem struct {
+	OrderId   int64 `venus:"column:order_id;PRIMARY KEY"`
+	ProductId int64 `venus:"column:product_id;PRIMARY KEY"`
+	Quantity  int32 `venus:"column:quantity"`
+}
+
+func TestCreateTable(t *testing.T) {
+	tests := []struct {
+		dialect string
+		opts    []TableOption
+		sqls    []string
+	}{
+		{
+			dialect: "mysql",
+			opts:    []TableOption{IfNotExists(), WithTableOptions("ENGINE=InnoDB DEFAULT CHARSET=utf8mb4")},
+			sqls: []string{
+				"CREATE TABLE IF NOT EXISTS `product` (`id` bigint PRIMARY KEY AUTO_INCREMENT, " +
+					"`code` varchar(32) NOT NULL UNIQUE COMMENT 'stock keeping unit', " +
+					"`price` decimal(10, 2) DEFAULT 0) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4",
+			},
+		},
+		{
+			dialect: "postgres",
+			sqls: []string{
+				`CREATE TABLE "product" ("id" bigserial PRIMARY KEY, "code" varchar(32) NOT NULL UNIQUE, ` +
+					`"price" numeric(10, 2) DEFAULT 0)`,
+				`COMMENT ON COLUMN "product"."code" IS 'stock keeping unit'`,
+			},
+		},
+		{
+			dialect: "sqlite3",
+			sqls: []string{
+				`CREATE TABLE "product" ("id" integer PRIMARY KEY AUTOINCREMENT, "code" text NOT NULL UNIQUE, ` +
+					`"price" numeric DEFAULT 0)`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.dialect, func(t *testing.T) {
+			db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+			require.NoError(t, err)
+			defer db.Close()
+
+			for _, sql := range tt.sqls {
+				mock.ExpectExec(sql).WillReturnResult(sqlmock.NewResult(0, 0))
+			}
+
+			dial, err := dialect.GetDialect(tt.dialect)
+			require.NoError(t, err)
+			assert.NoError(t, New[Product](db, dial).CreateTable(tt.opts...))
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestCreateTableCompositePrimaryKey(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectExec("CREATE TABLE `order_item` (`order_id` bigint, `product_id` bigint, `quantity` int, " +
+		"PRIMARY KEY (`order_id`, `product_id`))").WillReturnResult(sqlmock.NewResult(0, 0))
+
+	dial, _ := dialect.GetDialect("mysql")
+	assert.NoError(t, New[OrderItem](db, dial).CreateTable())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreateTableDefaultPrimaryKey(t *testing.T) {
+	type Tag struct {
+		Id   int64
+		Name string
+	}
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer db.Close()
+
+	// the id convention does not change the DDL
+	mock.ExpectExec("CREATE TABLE `tag` (`id` bigint, `name` varchar(255))").WillReturnResult(sqlmock.NewResult(0, 0))
+
+	dial, _ := dialect.GetDialect("mysql")
+	assert.NoError(t, New[Tag](db, dial).CreateTable())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreateTableGeneratedPrimaryKey(t *testing.T) {
+	type Tag struct {
+		Id   int64 `venus:"PRIMARY KEY"`
+		Name string
+	}
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer db.Close()
+
+	// Create leaves a single integer primary key to the database, so the DDL generates it
+	mock.ExpectExec("CREATE TABLE `tag` (`id` bigint PRIMARY KEY AUTO_INCREMENT, `name` varchar(255))").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`CREATE TABLE "tag" ("id" bigserial PRIMARY KEY, "name" text)`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	for _, name := range []string{"mysql", "postgres"} {
+		dial, _ := dialect.GetDialect(name)
+		assert.NoError(t, New[Tag](db, dial).CreateTable())
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSQLiteCreateTableAutoIncrement(t *testing.T) {
+	type Line struct {
+		OrderId int64 `venus:"PRIMARY KEY;AUTOINCREMENT"`
+		No      int64 `venus:"PRIMARY KEY"`
+	}
+
+	// sqlite only accepts AUTOINCREMENT on an inline INTEGER PRIMARY KEY
+	s := newSQLiteSession[Line](t)
+	assert.True(t, s.HasTable())
+}
+
+func TestSQLiteCreateTable(t *testing.T) {
+	s := newSQLiteSession[Product](t)
+	assert.NoError(t, s.CreateTable(IfNotExists()))
+
+	_, err := s.Raw(`INSERT INTO product (code) VALUES (?)`, "A-1").Exec()
+	require.NoError(t, err)
+	_, err = s.Raw(`INSERT INTO product (code) VALUES (?)`, "A-1").Exec()
+	assert.Error(t, err, "code is unique")
+
+	products, err := s.Select()
+	require.NoError(t, err)
+	assert.Equal(t, []Product{{Id: 1, Code: "A-1"}}, products)
+}
+
+type Event struct {
+	Id   int64  `venus:"PRIMARY KEY"`
+	Name string `venus:"index"`
+}
+
+func TestSQLiteTable(t *testing.T) {
+	s := newSQLiteSession[Event](t)
+
+	for _, name := range []string{"events_202610", "events_202611"} {
+		require.NoError(t, s.Table(name).AutoMigrate())
+		assert.True(t, s.Table(name).HasTable())
+	}
+
+	_, err := s.Table("events_202610").Insert(Event{Id: 1, Name: "a"}, Event{Id: 2, Name: "b"})
+	require.NoError(t, err)
+	_, err = s.Table("events_202611").InsertInBatches(context.Background(), []Event{{Id: 3, Name: "c"}, {Id: 5, Name: "e"}}, 1)
+	require.NoError(t, err)
+	count, err := s.Table("events_202611").Count()
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, count)
+
+	count, err = s.Table("events_202610").Count()
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, count)
+	events, err := s.Where("name = ?", "b").Table("events_202610").Select()
+	require.NoError(t, err)
+	assert.Equal(t, []Event{{Id: 2, Name: "b"}}, events)
+
+	// the table and the conditions only apply to the statement they were set for
+	_, err = s.Insert(Event{Id: 4, Name: "d"})
+	require.NoError(t, err)
+	events, err = s.Select()
+	require.NoError(t, err)
+	assert.Equal(t, []Event{{Id: 4, Name: "d"}}, events, "the model table is untouched")
+
+	require.NoError(t, s.Table("events_202611").DropTable())
+	assert.False(t, s.Table("events_202611").HasTable())
+}
@@ -8,18 +8,40 @@ import (
 
 func (d *DB[T]) cloneDB() *DB[T] {
 	return &DB[T]{
-		model:    d.model,
-		db:       d.db,
-		Sql:      d.Sql,
-		SqlVars:  d.SqlVars,
-		dialect:  d.dialect,
-		refTable: d.refTable,
-		Clause:   d.Clause,
+		model:     d.model,
+		DestType:  d.DestType,
+		db:        d.db,
+		Sql:       d.Sql,
+		SqlVars:   d.SqlVars,
+		dialect:   d.dialect,
+		refTable:  d.refTable,
+		table:     d.table,
+		selects:   append([]string(nil), d.selects...),
+		omits:     append([]string(nil), d.omits...),
+		where:     d.where,
+		joins:     append([]interface{}(nil), d.joins...),
+		groups:    append([]string(nil), d.groups...),
+		having:    d.having,
+		projects:  append([]string(nil), d.projects...),
+		track:     d.track,
+		registry:  d.registry,
+		snapshots: d.snapshots,
+		Clause:    d.Clause,
 	}
 }
 func (s *Session[T]) Transaction(txFn func(tx *Tx[T]) error) (err error) {
+	return s.TransactionContext(context.Background(), txFn)
+}
+
+func (s *Session[T]) TransactionContext(ctx context.Context, txFn func(tx *Tx[T]) error) (err error) {
+	return s.transaction(ctx, txFn)
+}
+
+// transaction runs txFn in a transaction begun from d, committed when txFn returns nil
+// and rolled back otherwise.
+func (d *DB[T]) transaction(ctx context.Context, txFn func(tx *Tx[T]) error) (err error) {
 	var tx *Tx[T]
-	if tx, err = s.Begin(); err != nil {
+	if tx, err = d.beginTx(ctx); err != nil {
 		return
 	}
 
@@ -30,7 +52,7 @@ func (s *Session[T]) Transaction(txFn func(tx *Tx[T]) error) (err error) {
 		} else if err != nil {
 			rollbackErr := tx.Rollback() // err is non-nil; don't change it
 			if rollbackErr != nil {
-				err = fmt.Errorf("excute err: %w, tx error: %s", err, rollbackErr.Error())
+				err = fmt.Errorf("execute err: %w, tx error: %s", err, rollbackErr.Error())
 			}
 		} else {
 			err = tx.Commit() // err is nil; if Commit returns error update err
@@ -49,20 +71,28 @@ func (s *Session[T]) Begin() (tx *Tx[T], err error) {
 }
 
 func (s *Session[T]) BeginTx(ctx context.Context) (tx *Tx[T], err error) {
+	return s.beginTx(ctx)
+}
+
+func (d *DB[T]) beginTx(ctx context.Context) (tx *Tx[T], err error) {
 	var beginTx *sql.Tx
-	beginTx, err = s.db.BeginTx(ctx, nil)
+	beginTx, err = d.db.BeginTx(ctx, nil)
 	if err != nil {
 		return
 	}
-	db := s.cloneDB()
+	db := d.cloneDB()
 	db.tx = beginTx
+	db.snapshots = newSnapshots(d.snapshots)
 	return &Tx[T]{
 		DB: db,
 	}, err
 }
 
 func (t *Tx[T]) Commit() (err error) {
-	err = t.tx.Commit()
+	if err = t.tx.Commit(); err != nil {
+		return
+	}
+	t.snapshots.commit()
 	return
 }
 